@@ -0,0 +1,221 @@
+package goapitosdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apito-io/types"
+)
+
+// AggregateFunc is an aggregate function computed over the documents of a bucket
+type AggregateFunc string
+
+// Supported aggregate functions
+const (
+	AggCount AggregateFunc = "count"
+	AggSum   AggregateFunc = "sum"
+	AggAvg   AggregateFunc = "avg"
+	AggMin   AggregateFunc = "min"
+	AggMax   AggregateFunc = "max"
+)
+
+// Aggregation describes one aggregate value computed per bucket
+type Aggregation struct {
+	Alias string        `json:"alias"`           // Name of the value in the result (default: "<func>_<field>")
+	Func  AggregateFunc `json:"func"`            // Aggregate function
+	Field string        `json:"field,omitempty"` // Data field to aggregate, not needed for count
+}
+
+// AggregateRequest describes an aggregation over the documents of a model
+type AggregateRequest struct {
+	Where        interface{}   // Where filter: a where.Expr or a map[string]interface{}
+	Search       string        // Full-text search term
+	Status       string        // Document status, e.g. "draft" or "published"
+	GroupBy      []string      // Data fields to group by; a single bucket when empty
+	Aggregations []Aggregation // Values computed per bucket; the document count is always included
+}
+
+// AggregateBucket holds the aggregate values of one group
+type AggregateBucket struct {
+	Key    map[string]interface{} `json:"key"`    // Group-by field values
+	Count  int                    `json:"count"`  // Number of documents in the bucket
+	Values map[string]float64     `json:"values"` // Aggregate values keyed by alias
+}
+
+// AggregateResult is the result of an aggregation
+type AggregateResult struct {
+	Count   int                // Total number of matching documents
+	Buckets []*AggregateBucket // One bucket per group
+}
+
+// TypedAggregateResult is the result of an aggregation with buckets decoded into T.
+// Each bucket is decoded from an object holding its group-by keys, its aggregate
+// values by alias and its document count under "count".
+type TypedAggregateResult[T any] struct {
+	Count   int
+	Buckets []T
+}
+
+// aggregateSpec is the JSON sent as the $aggregate variable
+type aggregateSpec struct {
+	GroupBy      []string      `json:"group_by,omitempty"`
+	Aggregations []Aggregation `json:"aggregations,omitempty"`
+}
+
+// Validate checks the request for unsupported functions and missing fields
+func (r AggregateRequest) Validate() error {
+	for _, field := range r.GroupBy {
+		if field == "" {
+			return newValidationError("group by field must not be empty")
+		}
+	}
+
+	aliases := make(map[string]bool, len(r.Aggregations))
+	for _, agg := range r.normalizedAggregations() {
+		switch agg.Func {
+		case AggCount:
+		case AggSum, AggAvg, AggMin, AggMax:
+			if agg.Field == "" {
+				return newValidationError("aggregate %s requires a field", agg.Func)
+			}
+		default:
+			return newValidationError("unsupported aggregate function %q", agg.Func)
+		}
+		if aliases[agg.Alias] {
+			return newValidationError("duplicate aggregate alias %q", agg.Alias)
+		}
+		aliases[agg.Alias] = true
+	}
+
+	return validateWhere(r.Where)
+}
+
+// normalizedAggregations returns the aggregations with default aliases filled in
+func (r AggregateRequest) normalizedAggregations() []Aggregation {
+	aggs := make([]Aggregation, len(r.Aggregations))
+	for i, agg := range r.Aggregations {
+		if agg.Alias == "" {
+			agg.Alias = string(agg.Func)
+			if agg.Field != "" {
+				agg.Alias += "_" + agg.Field
+			}
+		}
+		aggs[i] = agg
+	}
+	return aggs
+}
+
+// Aggregate computes group-by buckets and aggregate values for the documents of a model
+func (c *Client) Aggregate(ctx context.Context, model string, request AggregateRequest) (*AggregateResult, error) {
+	if model == "" {
+		return nil, newValidationError("model is required")
+	}
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query AggregateModelData($model: String!, $where: JSON, $search: String, $status: String, $aggregate: JSON) {
+			getModelData(model: $model, where: $where, search: $search, status: $status, aggregate: $aggregate) {
+				count
+				aggregate
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"model": model,
+		"aggregate": aggregateSpec{
+			GroupBy:      request.GroupBy,
+			Aggregations: request.normalizedAggregations(),
+		},
+	}
+	if request.Where != nil {
+		variables["where"] = request.Where
+	}
+	if request.Search != "" {
+		variables["search"] = request.Search
+	}
+	if request.Status != "" {
+		variables["status"] = request.Status
+	}
+
+	var raw *struct {
+		Count     int                `json:"count"`
+		Aggregate []*AggregateBucket `json:"aggregate"`
+	}
+	response, err := c.executeGraphQLInto(ctx, query, variables, map[string]interface{}{"getModelData": &raw})
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate resources: %w", err)
+	}
+
+	if err := decodeResponseField(response, "getModelData", &raw); err != nil {
+		return nil, err
+	}
+
+	return &AggregateResult{Count: raw.Count, Buckets: raw.Aggregate}, nil
+}
+
+// AggregateTyped computes an aggregation and decodes every bucket into T
+func AggregateTyped[T any](c *Client, ctx context.Context, model string, request AggregateRequest) (*TypedAggregateResult[T], error) {
+	result, err := c.Aggregate(ctx, model, request)
+	if err != nil {
+		return nil, err
+	}
+
+	typed := &TypedAggregateResult[T]{
+		Count:   result.Count,
+		Buckets: make([]T, len(result.Buckets)),
+	}
+	for i, bucket := range result.Buckets {
+		flat := make(map[string]interface{}, len(bucket.Key)+len(bucket.Values)+1)
+		for k, v := range bucket.Key {
+			flat[k] = v
+		}
+		for k, v := range bucket.Values {
+			flat[k] = v
+		}
+		flat["count"] = bucket.Count
+
+		bucketJSON, err := json.Marshal(flat)
+		if err != nil {
+			return nil, &DecodeError{Field: "aggregate", Err: err}
+		}
+		if err := json.Unmarshal(bucketJSON, &typed.Buckets[i]); err != nil {
+			return nil, &DecodeError{Field: "aggregate", Err: fmt.Errorf("bucket %d: %w", i, err)}
+		}
+	}
+
+	return typed, nil
+}
+
+// Count returns the number of documents matching the search options without fetching them
+func (c *Client) Count(ctx context.Context, model string, opts SearchOptions) (int, error) {
+	result, err := c.countOnly(ctx, model, opts)
+	if err != nil {
+		return 0, err
+	}
+	return result.Count, nil
+}
+
+// countOnly runs a search that only selects the count
+func (c *Client) countOnly(ctx context.Context, model string, opts SearchOptions) (*types.SearchResult, error) {
+	if err := c.validateSearch(model, opts); err != nil {
+		return nil, err
+	}
+
+	opts.Page, opts.Limit, opts.Sort, opts.Fields = 0, 0, nil, nil
+	var searchResult *types.SearchResult
+	variables := opts.variables(model)
+	response, err := c.executeGraphQLInto(ctx, buildSearchQuery(variables, "\n\t\t\t\tcount"), variables, map[string]interface{}{"getModelData": &searchResult})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count resources: %w", err)
+	}
+
+	if err := decodeResponseField(response, "getModelData", &searchResult); err != nil {
+		return nil, err
+	}
+
+	return searchResult, nil
+}
@@ -0,0 +1,86 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/apito-io/go-internal-sdk/where"
+)
+
+func TestAggregateTyped(t *testing.T) {
+	var requests []capturedRequest
+	server := newCapturingServer(t, `{"data":{"getModelData":{"count":5,"aggregate":[
+		{"key":{"status":"done"},"count":3,"values":{"total":30,"max_price":15}},
+		{"key":{"status":"open"},"count":2,"values":{"total":8,"max_price":5}}
+	]}}}`, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	type statusTotals struct {
+		Status   string  `json:"status"`
+		Count    int     `json:"count"`
+		Total    float64 `json:"total"`
+		MaxPrice float64 `json:"max_price"`
+	}
+
+	result, err := AggregateTyped[statusTotals](client, context.Background(), "order", AggregateRequest{
+		Where:   where.Field("archived").Eq(false),
+		GroupBy: []string{"status"},
+		Aggregations: []Aggregation{
+			{Alias: "total", Func: AggSum, Field: "price"},
+			{Func: AggMax, Field: "price"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("AggregateTyped failed: %v", err)
+	}
+
+	if result.Count != 5 || len(result.Buckets) != 2 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	if b := result.Buckets[0]; b.Status != "done" || b.Count != 3 || b.Total != 30 || b.MaxPrice != 15 {
+		t.Errorf("Unexpected first bucket: %+v", b)
+	}
+
+	spec, _ := requests[0].Variables["aggregate"].(map[string]interface{})
+	aggs, _ := spec["aggregations"].([]interface{})
+	if len(aggs) != 2 || aggs[1].(map[string]interface{})["alias"] != "max_price" {
+		t.Errorf("Expected default alias to be sent, got %v", spec)
+	}
+}
+
+func TestAggregateValidation(t *testing.T) {
+	client := NewClient(Config{BaseURL: "http://127.0.0.1:0", APIKey: "test-key"})
+
+	invalid := []AggregateRequest{
+		{Aggregations: []Aggregation{{Func: AggSum}}},
+		{Aggregations: []Aggregation{{Func: "median", Field: "price"}}},
+		{Aggregations: []Aggregation{{Func: AggMin, Field: "price"}, {Func: AggMin, Field: "price"}}},
+		{GroupBy: []string{""}},
+	}
+	for _, request := range invalid {
+		if _, err := client.Aggregate(context.Background(), "order", request); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected validation error for %+v, got %v", request, err)
+		}
+	}
+}
+
+func TestSearchResourcesAggregateIgnored(t *testing.T) {
+	var requests []capturedRequest
+	server := newCapturingServer(t, `{"data":{"getModelData":{"results":[{"id":"t1","data":{}}],"count":42}}}`, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	for _, aggregate := range []bool{false, true} {
+		result, err := client.SearchResources(context.Background(), "task", map[string]interface{}{"page": 3, "limit": 10}, aggregate)
+		if err != nil {
+			t.Fatalf("SearchResources failed: %v", err)
+		}
+		if result.Count != 42 || len(result.Results) != 1 {
+			t.Errorf("aggregate=%v: unexpected result: %+v", aggregate, result)
+		}
+	}
+	if requests[0].Query != requests[1].Query || !reflect.DeepEqual(requests[0].Variables, requests[1].Variables) {
+		t.Errorf("Expected aggregate to leave the request unchanged, got:\n%s\n%s", requests[0].Query, requests[1].Query)
+	}
+}
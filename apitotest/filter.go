@@ -0,0 +1,364 @@
+package apitotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// whereOperators are the condition operators understood in where filters
+var whereOperators = map[string]bool{
+	"eq": true, "ne": true, "gt": true, "gte": true, "lt": true, "lte": true,
+	"in": true, "not_in": true, "contains": true, "not_contains": true,
+	"starts_with": true, "ends_with": true, "exists": true,
+}
+
+// normalize converts a variable to its JSON form so that typed values such as where
+// expressions are handled like the server would receive them
+func normalize(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	err = json.Unmarshal(data, &generic)
+	return generic, err
+}
+
+// matchWhere reports whether a document satisfies a where filter in the SDK format:
+// {"field": {"op": value}}, nested objects for dotted fields, and "AND"/"OR" lists
+func matchWhere(doc *Document, filter map[string]interface{}) (bool, error) {
+	return matchObject(doc, nil, filter)
+}
+
+func matchObject(doc *Document, path []string, filter map[string]interface{}) (bool, error) {
+	for key, value := range filter {
+		var ok bool
+		var err error
+
+		switch {
+		case key == "AND" || key == "OR":
+			ok, err = matchList(doc, path, key, value)
+		case isCondition(value):
+			ok, err = matchCondition(doc.lookup(append(append([]string{}, path...), key)), value.(map[string]interface{}))
+		default:
+			nested, isMap := value.(map[string]interface{})
+			if !isMap {
+				return false, fmt.Errorf("invalid where value for %q", key)
+			}
+			ok, err = matchObject(doc, append(append([]string{}, path...), key), nested)
+		}
+
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchList(doc *Document, path []string, key string, value interface{}) (bool, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return false, fmt.Errorf("%s expects a list", key)
+	}
+	for _, item := range items {
+		filter, ok := item.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("%s expects a list of objects", key)
+		}
+		matched, err := matchObject(doc, path, filter)
+		if err != nil {
+			return false, err
+		}
+		if key == "OR" && matched {
+			return true, nil
+		}
+		if key == "AND" && !matched {
+			return false, nil
+		}
+	}
+	return key == "AND", nil
+}
+
+// isCondition reports whether value is an operator object such as {"eq": 1}
+func isCondition(value interface{}) bool {
+	m, ok := value.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return false
+	}
+	for key := range m {
+		if !whereOperators[key] {
+			return false
+		}
+	}
+	return true
+}
+
+func matchCondition(actual interface{}, condition map[string]interface{}) (bool, error) {
+	for op, expected := range condition {
+		var ok bool
+		switch op {
+		case "eq":
+			ok = equal(actual, expected)
+		case "ne":
+			ok = !equal(actual, expected)
+		case "gt", "gte", "lt", "lte":
+			cmp, comparable := compare(actual, expected)
+			if !comparable {
+				return false, nil
+			}
+			ok = op == "gt" && cmp > 0 || op == "gte" && cmp >= 0 || op == "lt" && cmp < 0 || op == "lte" && cmp <= 0
+		case "in", "not_in":
+			values, isList := expected.([]interface{})
+			if !isList {
+				return false, fmt.Errorf("%s expects a list", op)
+			}
+			found := false
+			for _, v := range values {
+				if equal(actual, v) {
+					found = true
+					break
+				}
+			}
+			ok = found == (op == "in")
+		case "contains", "not_contains":
+			ok = contains(actual, expected) == (op == "contains")
+		case "starts_with", "ends_with":
+			s, isString := actual.(string)
+			prefix, _ := expected.(string)
+			ok = isString && (op == "starts_with" && strings.HasPrefix(s, prefix) || op == "ends_with" && strings.HasSuffix(s, prefix))
+		case "exists":
+			want, _ := expected.(bool)
+			ok = (actual != nil) == want
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func equal(a, b interface{}) bool {
+	if cmp, ok := compare(a, b); ok {
+		return cmp == 0
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// compare orders two numbers or two strings
+func compare(a, b interface{}) (int, bool) {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+		return 0, false
+	}
+	x, ok1 := a.(string)
+	y, ok2 := b.(string)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// contains checks substring containment for strings and membership for lists
+func contains(actual, expected interface{}) bool {
+	switch value := actual.(type) {
+	case string:
+		s, ok := expected.(string)
+		return ok && strings.Contains(value, s)
+	case []interface{}:
+		for _, item := range value {
+			if equal(item, expected) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchSearch reports whether any string value of the document data contains term, ignoring case
+func matchSearch(data interface{}, term string) bool {
+	switch value := data.(type) {
+	case string:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(term))
+	case map[string]interface{}:
+		for _, item := range value {
+			if matchSearch(item, term) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if matchSearch(item, term) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortDocuments orders documents by data fields; insertion order breaks ties
+func sortDocuments(docs []*Document, order map[string]interface{}) {
+	keys := make([]string, 0, len(order))
+	for key := range order {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		for _, key := range keys {
+			direction, _ := toFloat(order[key])
+			path := strings.Split(key, ".")
+			cmp, ok := compare(docs[i].lookup(path), docs[j].lookup(path))
+			if !ok || cmp == 0 {
+				continue
+			}
+			if direction < 0 {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
+
+// project keeps only the given dotted paths of data
+func project(data map[string]interface{}, fields []interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, field := range fields {
+		name, ok := field.(string)
+		if !ok {
+			continue
+		}
+		path := strings.Split(name, ".")
+		src, dst := data, result
+		for i, key := range path {
+			value, ok := src[key]
+			if !ok {
+				break
+			}
+			if i == len(path)-1 {
+				dst[key] = value
+				break
+			}
+			next, ok := value.(map[string]interface{})
+			if !ok {
+				break
+			}
+			if _, ok := dst[key].(map[string]interface{}); !ok {
+				dst[key] = map[string]interface{}{}
+			}
+			src, dst = next, dst[key].(map[string]interface{})
+		}
+	}
+	return result
+}
+
+// aggregate computes the buckets requested by the $aggregate variable:
+// {"group_by": [...], "aggregations": [{"alias", "func", "field"}]}
+func aggregate(docs []*Document, spec map[string]interface{}) ([]interface{}, error) {
+	groupBy, _ := spec["group_by"].([]interface{})
+	aggregations, _ := spec["aggregations"].([]interface{})
+
+	type bucket struct {
+		key  map[string]interface{}
+		docs []*Document
+	}
+	var buckets []*bucket
+	index := map[string]*bucket{}
+	for _, doc := range docs {
+		key := map[string]interface{}{}
+		for _, field := range groupBy {
+			name, _ := field.(string)
+			key[name] = doc.lookup(strings.Split(name, "."))
+		}
+		id := fmt.Sprint(key)
+		b, ok := index[id]
+		if !ok {
+			b = &bucket{key: key}
+			index[id] = b
+			buckets = append(buckets, b)
+		}
+		b.docs = append(b.docs, doc)
+	}
+
+	result := make([]interface{}, 0, len(buckets))
+	for _, b := range buckets {
+		values := map[string]interface{}{}
+		for _, raw := range aggregations {
+			agg, ok := raw.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid aggregation %v", raw)
+			}
+			alias, _ := agg["alias"].(string)
+			fn, _ := agg["func"].(string)
+			field, _ := agg["field"].(string)
+
+			var numbers []float64
+			for _, doc := range b.docs {
+				if n, ok := toFloat(doc.lookup(strings.Split(field, "."))); ok {
+					numbers = append(numbers, n)
+				}
+			}
+
+			switch fn {
+			case "count":
+				values[alias] = len(b.docs)
+			case "sum", "avg":
+				sum := 0.0
+				for _, n := range numbers {
+					sum += n
+				}
+				if fn == "avg" && len(numbers) > 0 {
+					sum /= float64(len(numbers))
+				}
+				values[alias] = sum
+			case "min", "max":
+				if len(numbers) == 0 {
+					values[alias] = 0
+					continue
+				}
+				extreme := numbers[0]
+				for _, n := range numbers[1:] {
+					if fn == "min" {
+						extreme = math.Min(extreme, n)
+					} else {
+						extreme = math.Max(extreme, n)
+					}
+				}
+				values[alias] = extreme
+			default:
+				return nil, fmt.Errorf("unsupported aggregate function %q", fn)
+			}
+		}
+		result = append(result, map[string]interface{}{"key": b.key, "count": len(b.docs), "values": values})
+	}
+	return result, nil
+}
@@ -0,0 +1,379 @@
+package apitotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/apito-io/types"
+	"github.com/apito-io/types/interfaces"
+)
+
+// ErrUnexpectedCall is returned by MockClient methods called without a matching stub
+var ErrUnexpectedCall = errors.New("apitotest: unexpected call")
+
+// MockClient is a programmable implementation of interfaces.InternalSDKOperation that
+// records every call:
+//
+//	mock := apitotest.NewMockClient()
+//	mock.On("SearchResources", apitotest.Model("product")).Return(&types.SearchResult{Count: 1}, nil)
+//	mock.On("UpdateResource", apitotest.Anything).ReturnError(goapitosdk.ErrNotFound).Once()
+//
+//	service := NewService(mock)
+//	// ...
+//	mock.AssertCalled(t, "UpdateResource", apitotest.Model("product"))
+//
+// Arguments are matched without the context. An expected argument is either a Matcher
+// or a value compared with reflect.DeepEqual; a stub registered without arguments
+// matches any call of its method. The most recently registered matching stub wins.
+type MockClient struct {
+	mu    sync.Mutex
+	stubs []*Stub
+	calls []Call
+}
+
+var _ interfaces.InternalSDKOperation = (*MockClient)(nil)
+
+// mockMethods maps the mocked methods to their number of return values, the last one being the error
+var mockMethods = map[string]int{
+	"GenerateTenantToken":  2,
+	"GetSingleResource":    2,
+	"SearchResources":      2,
+	"GetRelationDocuments": 2,
+	"CreateNewResource":    2,
+	"UpdateResource":       2,
+	"DeleteResource":       1,
+	"Debug":                2,
+}
+
+// NewMockClient creates a mock without any stub
+func NewMockClient() *MockClient {
+	return &MockClient{}
+}
+
+// Call is a recorded call. Args excludes the context; variadic arguments are passed as a slice.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Stub is the programmed response of a method
+type Stub struct {
+	mu      *sync.Mutex // The mock's mutex, guarding the stub against concurrent calls
+	method  string
+	args    []interface{}
+	returns []interface{}
+	run     func(Call)
+	times   int // Remaining uses, 0 for unlimited
+	limited bool
+	calls   int
+}
+
+// On registers a stub for method, matching the given arguments. It panics on unknown methods.
+func (m *MockClient) On(method string, args ...interface{}) *Stub {
+	arity, ok := mockMethods[method]
+	if !ok {
+		panic(fmt.Sprintf("apitotest: %s is not a method of interfaces.InternalSDKOperation", method))
+	}
+
+	stub := &Stub{mu: &m.mu, method: method, args: args, returns: make([]interface{}, arity)}
+	m.mu.Lock()
+	m.stubs = append(m.stubs, stub)
+	m.mu.Unlock()
+	return stub
+}
+
+// Return sets the values returned by the stub, in the order of the method results
+func (s *Stub) Return(values ...interface{}) *Stub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(values) != len(s.returns) {
+		panic(fmt.Sprintf("apitotest: %s returns %d values, got %d", s.method, len(s.returns), len(values)))
+	}
+	s.returns = values
+	return s
+}
+
+// ReturnError makes the stub return zero values and err
+func (s *Stub) ReturnError(err error) *Stub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.returns = make([]interface{}, len(s.returns))
+	s.returns[len(s.returns)-1] = err
+	return s
+}
+
+// Run sets a function called with every matching call before the values are returned
+func (s *Stub) Run(fn func(call Call)) *Stub {
+	s.mu.Lock()
+	s.run = fn
+	s.mu.Unlock()
+	return s
+}
+
+// Once limits the stub to a single call
+func (s *Stub) Once() *Stub {
+	return s.Times(1)
+}
+
+// Times limits the stub to n calls
+func (s *Stub) Times(n int) *Stub {
+	s.mu.Lock()
+	s.times, s.limited = n, true
+	s.mu.Unlock()
+	return s
+}
+
+// Matcher matches a call argument
+type Matcher interface {
+	Match(arg interface{}) bool
+	String() string
+}
+
+type matcherFunc struct {
+	description string
+	match       func(arg interface{}) bool
+}
+
+func (m matcherFunc) Match(arg interface{}) bool {
+	return m.match(arg)
+}
+
+func (m matcherFunc) String() string {
+	return m.description
+}
+
+// Anything matches any argument
+var Anything Matcher = matcherFunc{"<anything>", func(interface{}) bool { return true }}
+
+// MatchedBy returns a matcher calling fn with the argument
+func MatchedBy(description string, fn func(arg interface{}) bool) Matcher {
+	return matcherFunc{description, fn}
+}
+
+// Model matches a model name argument, or a *types.CreateAndUpdateRequest for that model
+func Model(name string) Matcher {
+	return matcherFunc{fmt.Sprintf("<model %s>", name), func(arg interface{}) bool {
+		switch v := arg.(type) {
+		case string:
+			return v == name
+		case *types.CreateAndUpdateRequest:
+			return v != nil && v.Model == name
+		}
+		return false
+	}}
+}
+
+// matches reports whether the actual arguments satisfy the expected ones
+func matches(expected, actual []interface{}) bool {
+	if len(expected) == 0 {
+		return true
+	}
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i, want := range expected {
+		if matcher, ok := want.(Matcher); ok {
+			if !matcher.Match(actual[i]) {
+				return false
+			}
+		} else if !reflect.DeepEqual(want, actual[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// call records a call and returns the values of the matching stub
+func (m *MockClient) call(method string, args ...interface{}) []interface{} {
+	call := Call{Method: method, Args: args}
+
+	m.mu.Lock()
+	m.calls = append(m.calls, call)
+	var stub *Stub
+	var run func(Call)
+	var returns []interface{}
+	for i := len(m.stubs) - 1; i >= 0; i-- {
+		s := m.stubs[i]
+		if s.method == method && (!s.limited || s.times > 0) && matches(s.args, args) {
+			stub = s
+			break
+		}
+	}
+	if stub != nil {
+		stub.calls++
+		if stub.limited {
+			stub.times--
+		}
+		run, returns = stub.run, stub.returns
+	}
+	m.mu.Unlock()
+
+	if stub == nil {
+		returns := make([]interface{}, mockMethods[method])
+		returns[len(returns)-1] = fmt.Errorf("%w: %s", ErrUnexpectedCall, formatCall(call))
+		return returns
+	}
+	if run != nil {
+		run(call)
+	}
+	return returns
+}
+
+// returnValue converts the i-th programmed value to the result type of the method
+func returnValue[T any](method string, values []interface{}, i int) T {
+	var zero T
+	if values[i] == nil {
+		return zero
+	}
+	v, ok := values[i].(T)
+	if !ok {
+		panic(fmt.Sprintf("apitotest: %s return value %d is %T, want %T", method, i, values[i], zero))
+	}
+	return v
+}
+
+// Calls returns the recorded calls of method, or every call when method is empty
+func (m *MockClient) Calls(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []Call
+	for _, call := range m.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// TestingT is the subset of testing.TB used by the assertion helpers
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertCalled checks that method was called with matching arguments
+func (m *MockClient) AssertCalled(t TestingT, method string, args ...interface{}) bool {
+	t.Helper()
+	calls := m.Calls(method)
+	for _, call := range calls {
+		if matches(args, call.Args) {
+			return true
+		}
+	}
+	t.Errorf("expected call %s, got:\n%s", formatCall(Call{Method: method, Args: args}), formatCalls(calls))
+	return false
+}
+
+// AssertNotCalled checks that method was never called with matching arguments
+func (m *MockClient) AssertNotCalled(t TestingT, method string, args ...interface{}) bool {
+	t.Helper()
+	for _, call := range m.Calls(method) {
+		if matches(args, call.Args) {
+			t.Errorf("unexpected call %s", formatCall(call))
+			return false
+		}
+	}
+	return true
+}
+
+// AssertNumberOfCalls checks how many times method was called
+func (m *MockClient) AssertNumberOfCalls(t TestingT, method string, n int) bool {
+	t.Helper()
+	if calls := m.Calls(method); len(calls) != n {
+		t.Errorf("expected %d calls of %s, got %d:\n%s", n, method, len(calls), formatCalls(calls))
+		return false
+	}
+	return true
+}
+
+// AssertExpectations checks that every stub limited with Once or Times was fully used
+func (m *MockClient) AssertExpectations(t TestingT) bool {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ok := true
+	for _, stub := range m.stubs {
+		if stub.limited && stub.times > 0 {
+			t.Errorf("expected %d more call(s) of %s", stub.times, formatCall(Call{Method: stub.method, Args: stub.args}))
+			ok = false
+		}
+	}
+	return ok
+}
+
+func formatCall(call Call) string {
+	args := make([]string, len(call.Args))
+	for i, arg := range call.Args {
+		if matcher, ok := arg.(Matcher); ok {
+			args[i] = matcher.String()
+		} else {
+			args[i] = fmt.Sprintf("%#v", arg)
+		}
+	}
+	return fmt.Sprintf("%s(%s)", call.Method, strings.Join(args, ", "))
+}
+
+func formatCalls(calls []Call) string {
+	if len(calls) == 0 {
+		return "\t(no calls)"
+	}
+	lines := make([]string, len(calls))
+	for i, call := range calls {
+		lines[i] = "\t" + formatCall(call)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GenerateTenantToken implements interfaces.InternalSDKOperation
+func (m *MockClient) GenerateTenantToken(ctx context.Context, token string, tenantID string) (string, error) {
+	r := m.call("GenerateTenantToken", token, tenantID)
+	return returnValue[string]("GenerateTenantToken", r, 0), returnValue[error]("GenerateTenantToken", r, 1)
+}
+
+// GetSingleResource implements interfaces.InternalSDKOperation
+func (m *MockClient) GetSingleResource(ctx context.Context, model, _id string, singlePageData bool) (*types.DefaultDocumentStructure, error) {
+	r := m.call("GetSingleResource", model, _id, singlePageData)
+	return returnValue[*types.DefaultDocumentStructure]("GetSingleResource", r, 0), returnValue[error]("GetSingleResource", r, 1)
+}
+
+// SearchResources implements interfaces.InternalSDKOperation
+func (m *MockClient) SearchResources(ctx context.Context, model string, filter map[string]interface{}, aggregate bool) (*types.SearchResult, error) {
+	r := m.call("SearchResources", model, filter, aggregate)
+	return returnValue[*types.SearchResult]("SearchResources", r, 0), returnValue[error]("SearchResources", r, 1)
+}
+
+// GetRelationDocuments implements interfaces.InternalSDKOperation
+func (m *MockClient) GetRelationDocuments(ctx context.Context, _id string, connection map[string]interface{}) (*types.SearchResult, error) {
+	r := m.call("GetRelationDocuments", _id, connection)
+	return returnValue[*types.SearchResult]("GetRelationDocuments", r, 0), returnValue[error]("GetRelationDocuments", r, 1)
+}
+
+// CreateNewResource implements interfaces.InternalSDKOperation
+func (m *MockClient) CreateNewResource(ctx context.Context, request *types.CreateAndUpdateRequest) (*types.DefaultDocumentStructure, error) {
+	r := m.call("CreateNewResource", request)
+	return returnValue[*types.DefaultDocumentStructure]("CreateNewResource", r, 0), returnValue[error]("CreateNewResource", r, 1)
+}
+
+// UpdateResource implements interfaces.InternalSDKOperation
+func (m *MockClient) UpdateResource(ctx context.Context, request *types.CreateAndUpdateRequest) (*types.DefaultDocumentStructure, error) {
+	r := m.call("UpdateResource", request)
+	return returnValue[*types.DefaultDocumentStructure]("UpdateResource", r, 0), returnValue[error]("UpdateResource", r, 1)
+}
+
+// DeleteResource implements interfaces.InternalSDKOperation
+func (m *MockClient) DeleteResource(ctx context.Context, model, _id string) error {
+	r := m.call("DeleteResource", model, _id)
+	return returnValue[error]("DeleteResource", r, 0)
+}
+
+// Debug implements interfaces.InternalSDKOperation
+func (m *MockClient) Debug(ctx context.Context, stage string, data ...interface{}) (interface{}, error) {
+	r := m.call("Debug", stage, data)
+	return returnValue[interface{}]("Debug", r, 0), returnValue[error]("Debug", r, 1)
+}
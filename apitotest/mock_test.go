@@ -0,0 +1,137 @@
+package apitotest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	goapitosdk "github.com/apito-io/go-internal-sdk"
+	"github.com/apito-io/go-internal-sdk/apitotest"
+	"github.com/apito-io/types"
+	"github.com/apito-io/types/interfaces"
+)
+
+// recordingT captures assertion failures instead of failing the test
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// archiveTask is an example of code under test depending on the SDK interface
+func archiveTask(ctx context.Context, sdk interfaces.InternalSDKOperation, id string) error {
+	task, err := sdk.GetSingleResource(ctx, "task", id, false)
+	if err != nil {
+		return err
+	}
+	task.Data["archived"] = true
+	_, err = sdk.UpdateResource(ctx, &types.CreateAndUpdateRequest{ID: id, Model: "task", Payload: task.Data})
+	return err
+}
+
+func TestMockClientStubsAndAssertions(t *testing.T) {
+	mock := apitotest.NewMockClient()
+	mock.On("GetSingleResource", "task", "t1", false).Return(&types.DefaultDocumentStructure{
+		ID:   "t1",
+		Data: map[string]interface{}{"title": "Write docs"},
+	}, nil)
+	mock.On("UpdateResource", apitotest.Model("task")).Return(&types.DefaultDocumentStructure{ID: "t1"}, nil).Once()
+
+	if err := archiveTask(context.Background(), mock, "t1"); err != nil {
+		t.Fatalf("archiveTask failed: %v", err)
+	}
+
+	mock.AssertCalled(t, "UpdateResource", apitotest.MatchedBy("archived payload", func(arg interface{}) bool {
+		request := arg.(*types.CreateAndUpdateRequest)
+		payload, _ := request.Payload.(map[string]interface{})
+		return payload["archived"] == true
+	}))
+	mock.AssertNumberOfCalls(t, "GetSingleResource", 1)
+	mock.AssertNotCalled(t, "DeleteResource")
+	mock.AssertExpectations(t)
+
+	// The Once stub is used up: the second update is unexpected
+	err := archiveTask(context.Background(), mock, "t1")
+	if !errors.Is(err, apitotest.ErrUnexpectedCall) || !strings.Contains(err.Error(), "UpdateResource") {
+		t.Errorf("Expected ErrUnexpectedCall for UpdateResource, got %v", err)
+	}
+}
+
+func TestMockClientPerModelResponses(t *testing.T) {
+	mock := apitotest.NewMockClient()
+	mock.On("SearchResources").Return(&types.SearchResult{Count: 0}, nil)
+	mock.On("SearchResources", apitotest.Model("product"), apitotest.Anything, false).Return(&types.SearchResult{Count: 3}, nil)
+	mock.On("DeleteResource", "product", "p1").ReturnError(goapitosdk.ErrNotFound)
+
+	products, _ := mock.SearchResources(context.Background(), "product", nil, false)
+	users, _ := mock.SearchResources(context.Background(), "user", nil, false)
+	if products.Count != 3 || users.Count != 0 {
+		t.Errorf("Expected per-model responses, got %d and %d", products.Count, users.Count)
+	}
+
+	if err := mock.DeleteResource(context.Background(), "product", "p1"); !errors.Is(err, goapitosdk.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	var ran []string
+	mock.On("Debug").Run(func(call apitotest.Call) { ran = append(ran, call.Args[0].(string)) }).Return("ok", nil)
+	if result, err := mock.Debug(context.Background(), "stage", 1, 2); result != "ok" || err != nil || len(ran) != 1 {
+		t.Errorf("Expected the Debug stub to run, got %v, %v", result, err)
+	}
+	if calls := mock.Calls("Debug"); len(calls) != 1 || len(calls[0].Args[1].([]interface{})) != 2 {
+		t.Errorf("Expected the variadic data to be recorded, got %+v", calls)
+	}
+}
+
+func TestMockClientAssertionFailures(t *testing.T) {
+	mock := apitotest.NewMockClient()
+	mock.On("CreateNewResource").ReturnError(errors.New("boom")).Times(2)
+	mock.CreateNewResource(context.Background(), &types.CreateAndUpdateRequest{Model: "task"})
+
+	rt := &recordingT{}
+	if mock.AssertCalled(rt, "CreateNewResource", apitotest.Model("product")) {
+		t.Error("Expected AssertCalled to fail for another model")
+	}
+	if mock.AssertNumberOfCalls(rt, "CreateNewResource", 2) {
+		t.Error("Expected AssertNumberOfCalls to fail")
+	}
+	if mock.AssertExpectations(rt) {
+		t.Error("Expected AssertExpectations to report the unused call")
+	}
+	if len(rt.errors) != 3 || !strings.Contains(rt.errors[0], "<model product>") {
+		t.Errorf("Unexpected failure messages: %q", rt.errors)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected On to panic for an unknown method")
+		}
+	}()
+	mock.On("SendAuditLog")
+}
+
+func TestMockClientStubsConcurrently(t *testing.T) {
+	mock := apitotest.NewMockClient()
+	stub := mock.On("Debug")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			stub.Return("ok", nil).Times(100)
+		}()
+		go func() {
+			defer wg.Done()
+			mock.Debug(context.Background(), "stage")
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,211 @@
+package apitotest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Patch types accepted by patchModelData
+const (
+	mergePatchType = "application/merge-patch+json"
+	jsonPatchType  = "application/json-patch+json"
+)
+
+func (s *Server) patchModelData(tenantID string, args map[string]interface{}) (interface{}, error) {
+	model, _ := args["model_name"].(string)
+	id, _ := args["_id"].(string)
+
+	doc := s.find(tenantID, model, id)
+	if doc == nil {
+		return nil, notFound(model, id)
+	}
+	if expected, _ := args["expected_revision"].(string); expected != "" && expected != doc.revision() {
+		return nil, &graphQLError{Message: fmt.Sprintf("document %q was modified since revision %s", doc.ID, expected), Code: "CONFLICT"}
+	}
+
+	var data map[string]interface{}
+	var err error
+	switch patchType, _ := args["patch_type"].(string); patchType {
+	case mergePatchType:
+		patch, ok := args["patch"].(map[string]interface{})
+		if !ok {
+			return nil, badInput("merge patch must be an object")
+		}
+		data = mergePatch(cloneMap(doc.Data), patch)
+	case jsonPatchType:
+		patch, ok := args["patch"].([]interface{})
+		if !ok {
+			return nil, badInput("JSON patch must be an array")
+		}
+		if data, err = applyJSONPatch(cloneMap(doc.Data), patch); err != nil {
+			return nil, badInput("%v", err)
+		}
+	default:
+		return nil, badInput("unsupported patch_type %q", patchType)
+	}
+
+	doc.Data = data
+	s.touch(doc)
+	s.saveRevision(doc)
+	return doc.response(nil), nil
+}
+
+// mergePatch applies an RFC 7396 merge patch to target in place
+func mergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	if target == nil {
+		target = make(map[string]interface{})
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			target[key] = value
+			continue
+		}
+		existing, _ := target[key].(map[string]interface{})
+		target[key] = mergePatch(existing, nested)
+	}
+	return target
+}
+
+// errTestFailed is reported when a "test" operation does not match
+var errTestFailed = errors.New("patch test failed")
+
+// applyJSONPatch applies the add, remove, replace and test operations of an RFC 6902 patch
+func applyJSONPatch(data map[string]interface{}, patch []interface{}) (map[string]interface{}, error) {
+	var doc interface{} = data
+	if data == nil {
+		doc = make(map[string]interface{})
+	}
+	for i, raw := range patch {
+		op, _ := raw.(map[string]interface{})
+		name, _ := op["op"].(string)
+		path, _ := op["path"].(string)
+		if name != "remove" {
+			if _, ok := op["value"]; !ok {
+				return nil, fmt.Errorf("patch operation %d (%s %s): value is required", i, name, path)
+			}
+		}
+
+		var tokens []string
+		if path != "" {
+			if !strings.HasPrefix(path, "/") {
+				return nil, fmt.Errorf("patch operation %d: invalid path %q", i, path)
+			}
+			tokens = strings.Split(path[1:], "/")
+			for j, token := range tokens {
+				tokens[j] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+			}
+		}
+
+		var err error
+		if doc, err = applyPatchOperation(doc, name, tokens, op["value"]); err != nil {
+			return nil, fmt.Errorf("patch operation %d (%s %s): %w", i, name, path, err)
+		}
+	}
+
+	result, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("patched document data is not an object")
+	}
+	return result, nil
+}
+
+// applyPatchOperation applies one operation to doc and returns the new root
+func applyPatchOperation(doc interface{}, op string, tokens []string, value interface{}) (interface{}, error) {
+	switch op {
+	case "add", "remove", "replace", "test":
+	default:
+		return nil, fmt.Errorf("unsupported op %q", op)
+	}
+	if len(tokens) == 0 {
+		switch op {
+		case "test":
+			if !reflect.DeepEqual(doc, value) {
+				return nil, errTestFailed
+			}
+			return doc, nil
+		case "remove":
+			return nil, fmt.Errorf("the document data cannot be removed")
+		}
+		return value, nil
+	}
+
+	parent := doc
+	for _, token := range tokens[:len(tokens)-1] {
+		switch container := parent.(type) {
+		case map[string]interface{}:
+			next, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q does not exist", token)
+			}
+			parent = next
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(container) {
+				return nil, fmt.Errorf("invalid array index %q", token)
+			}
+			parent = container[index]
+		default:
+			return nil, fmt.Errorf("cannot address %q inside a %T", token, parent)
+		}
+	}
+	key := tokens[len(tokens)-1]
+
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		current, exists := container[key]
+		if !exists && op != "add" {
+			if op == "test" {
+				return nil, errTestFailed
+			}
+			return nil, fmt.Errorf("member %q does not exist", key)
+		}
+		switch op {
+		case "add", "replace":
+			container[key] = value
+		case "remove":
+			delete(container, key)
+		case "test":
+			if !reflect.DeepEqual(current, value) {
+				return nil, errTestFailed
+			}
+		}
+		return doc, nil
+
+	case []interface{}:
+		index := len(container)
+		if key != "-" || op != "add" {
+			var err error
+			index, err = strconv.Atoi(key)
+			if err != nil || index < 0 || index > len(container) || index == len(container) && op != "add" {
+				return nil, fmt.Errorf("invalid array index %q", key)
+			}
+		}
+		var updated []interface{}
+		switch op {
+		case "add":
+			updated = append(container[:index:index], append([]interface{}{value}, container[index:]...)...)
+		case "remove":
+			updated = append(container[:index:index], container[index+1:]...)
+		case "replace":
+			container[index] = value
+			return doc, nil
+		case "test":
+			if !reflect.DeepEqual(container[index], value) {
+				return nil, errTestFailed
+			}
+			return doc, nil
+		}
+		// Arrays change length, so the new array is stored in its parent
+		return applyPatchOperation(doc, "replace", tokens[:len(tokens)-1], updated)
+	}
+
+	return nil, fmt.Errorf("cannot address %q inside a %T", key, parent)
+}
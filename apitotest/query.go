@@ -0,0 +1,214 @@
+package apitotest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// rootField is a top-level field of a GraphQL operation with its arguments resolved
+type rootField struct {
+	Alias string // Response key: the alias, or the name when not aliased
+	Name  string
+	Args  map[string]interface{}
+}
+
+// parseRootFields extracts the top-level fields of a GraphQL document and resolves their
+// arguments against the variables. Only the subset of GraphQL emitted by the SDK is
+// supported: one operation, variable or scalar literal arguments, and selection sets,
+// which are skipped since resolvers always return complete objects.
+func parseRootFields(query string, variables map[string]interface{}) ([]rootField, error) {
+	p := &parser{src: query}
+
+	// Skip the operation header, including variable definitions
+	start := strings.IndexByte(query, '{')
+	if start < 0 {
+		return nil, fmt.Errorf("missing selection set")
+	}
+	p.pos = start + 1
+
+	var fields []rootField
+	for {
+		p.skipIgnored()
+		if p.eof() {
+			return nil, fmt.Errorf("unterminated selection set")
+		}
+		if p.peek() == '}' {
+			return fields, nil
+		}
+
+		name := p.name()
+		if name == "" {
+			return nil, fmt.Errorf("unexpected %q at offset %d", p.peek(), p.pos)
+		}
+		field := rootField{Alias: name, Name: name, Args: map[string]interface{}{}}
+
+		p.skipIgnored()
+		if p.peek() == ':' {
+			p.pos++
+			p.skipIgnored()
+			field.Name = p.name()
+			if field.Name == "" {
+				return nil, fmt.Errorf("missing field name after alias %q", name)
+			}
+			p.skipIgnored()
+		}
+
+		if p.peek() == '(' {
+			p.pos++
+			if err := p.arguments(field.Args, variables); err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			p.skipIgnored()
+		}
+
+		if p.peek() == '{' {
+			if err := p.skipBlock(); err != nil {
+				return nil, err
+			}
+		}
+
+		fields = append(fields, field)
+	}
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// skipIgnored skips whitespace, commas and comments
+func (p *parser) skipIgnored() {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ',' || unicode.IsSpace(rune(c)):
+			p.pos++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) name() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' && p.pos > start {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.src[start:p.pos]
+}
+
+// arguments parses "name: value" pairs up to the closing parenthesis
+func (p *parser) arguments(args map[string]interface{}, variables map[string]interface{}) error {
+	for {
+		p.skipIgnored()
+		if p.eof() {
+			return fmt.Errorf("unterminated arguments")
+		}
+		if p.peek() == ')' {
+			p.pos++
+			return nil
+		}
+
+		name := p.name()
+		p.skipIgnored()
+		if name == "" || p.peek() != ':' {
+			return fmt.Errorf("malformed argument at offset %d", p.pos)
+		}
+		p.pos++
+		p.skipIgnored()
+
+		value, err := p.value(variables)
+		if err != nil {
+			return fmt.Errorf("argument %s: %w", name, err)
+		}
+		if value != nil {
+			args[name] = value
+		}
+	}
+}
+
+// value parses a variable reference or a scalar literal
+func (p *parser) value(variables map[string]interface{}) (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '$':
+		p.pos++
+		return variables[p.name()], nil
+	case c == '"':
+		end := p.pos + 1
+		for end < len(p.src) && p.src[end] != '"' {
+			if p.src[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(p.src) {
+			return nil, fmt.Errorf("unterminated string")
+		}
+		s, err := strconv.Unquote(p.src[p.pos : end+1])
+		p.pos = end + 1
+		return s, err
+	case c == '-' || c >= '0' && c <= '9':
+		start := p.pos
+		p.pos++
+		for !p.eof() && strings.IndexByte("0123456789.eE+-", p.peek()) >= 0 {
+			p.pos++
+		}
+		return strconv.ParseFloat(p.src[start:p.pos], 64)
+	}
+
+	switch literal := p.name(); literal {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported literal %q", literal)
+	}
+}
+
+// skipBlock skips a balanced selection set
+func (p *parser) skipBlock() error {
+	depth := 0
+	for !p.eof() {
+		switch p.peek() {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				p.pos++
+				return nil
+			}
+		case '"':
+			p.pos++
+			for !p.eof() && p.peek() != '"' {
+				p.pos++
+			}
+		}
+		p.pos++
+	}
+	return fmt.Errorf("unterminated selection set")
+}
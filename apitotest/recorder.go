@@ -0,0 +1,346 @@
+package apitotest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrNoInteraction is returned in replay mode when no recorded interaction matches a request
+var ErrNoInteraction = errors.New("apitotest: no recorded interaction matches the request")
+
+// scrubbed replaces secrets in recorded cassettes
+const scrubbed = "[SCRUBBED]"
+
+// RecorderMode selects whether a Recorder talks to the real server
+type RecorderMode int
+
+const (
+	// ModeReplay answers every request from the cassette and never touches the network
+	ModeReplay RecorderMode = iota
+	// ModeRecord forwards requests to the real server and saves the exchanges on Close
+	ModeRecord
+)
+
+// RecorderOptions configures a Recorder
+type RecorderOptions struct {
+	Mode            RecorderMode
+	Transport       http.RoundTripper // Transport used in record mode (default: http.DefaultTransport)
+	ScrubFields     []string          // Extra variable and response fields to scrub, matched case-insensitively at any depth
+	IgnoreVariables []string          // Top-level variables left out of request matching, e.g. timestamps
+	T               TestingT          // Reports unmatched requests as test failures when set
+}
+
+// defaultScrubbedFields are always scrubbed from recorded variables and responses
+var defaultScrubbedFields = []string{"token", "password", "secret", "api_key", "apiKey"}
+
+// recordedHeaders are the only request headers stored in cassettes
+var recordedHeaders = []string{"Content-Type", "X-Apito-Tenant-ID", "Accept-Language"}
+
+// Cassette is the file format of recorded exchanges
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded GraphQL exchange
+type Interaction struct {
+	Operation string                 `json:"operation"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+	Query     string                 `json:"query"`
+	Header    map[string]string      `json:"header,omitempty"`
+	Response  RecordedResponse       `json:"response"`
+
+	used bool
+}
+
+// RecordedResponse is the recorded HTTP response of an interaction
+type RecordedResponse struct {
+	StatusCode int               `json:"status_code"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"` // JSON bodies, scrubbed
+	Text       string            `json:"text,omitempty"` // Non-JSON bodies, such as plain-text errors
+}
+
+// body returns the response body to replay
+func (r RecordedResponse) body() []byte {
+	if r.Body != nil {
+		return r.Body
+	}
+	return []byte(r.Text)
+}
+
+// Recorder is an http.RoundTripper recording GraphQL exchanges to a cassette file and
+// replaying them, for deterministic integration tests:
+//
+//	mode := apitotest.ModeReplay
+//	if os.Getenv("APITO_RECORD") != "" {
+//		mode = apitotest.ModeRecord
+//	}
+//	recorder, err := apitotest.NewRecorder("testdata/search.json", apitotest.RecorderOptions{Mode: mode, T: t})
+//	defer recorder.Close()
+//	client := goapitosdk.NewClient(goapitosdk.Config{BaseURL: url, APIKey: key, HTTPClient: recorder.HTTPClient()})
+//
+// Requests are matched on operation name, tenant header, query and variables. Queries
+// are compared with their whitespace collapsed, and variables in their JSON form after
+// scrubbing. Identical requests are replayed in recording order, each interaction
+// being used once. API keys, authorization headers and token fields never reach the file.
+type Recorder struct {
+	path     string
+	opts     RecorderOptions
+	scrub    map[string]struct{}
+	ignore   map[string]struct{}
+	mu       sync.Mutex
+	cassette *Cassette
+}
+
+// NewRecorder creates a recorder for the cassette at path. In replay mode the cassette
+// must exist; in record mode it is overwritten on Close.
+func NewRecorder(path string, opts RecorderOptions) (*Recorder, error) {
+	r := &Recorder{
+		path:     path,
+		opts:     opts,
+		scrub:    make(map[string]struct{}),
+		ignore:   make(map[string]struct{}),
+		cassette: &Cassette{},
+	}
+	if r.opts.Transport == nil {
+		r.opts.Transport = http.DefaultTransport
+	}
+	for _, names := range [][]string{defaultScrubbedFields, opts.ScrubFields} {
+		for _, name := range names {
+			r.scrub[strings.ToLower(name)] = struct{}{}
+		}
+	}
+	for _, name := range opts.IgnoreVariables {
+		r.ignore[name] = struct{}{}
+	}
+
+	if opts.Mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, r.cassette); err != nil {
+			return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+		}
+	}
+
+	return r, nil
+}
+
+// HTTPClient returns an HTTP client using the recorder, for Config.HTTPClient
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	var gqlReq graphQLRequest
+	if err := json.Unmarshal(body, &gqlReq); err != nil {
+		return nil, fmt.Errorf("failed to decode GraphQL request: %w", err)
+	}
+	operation := operationName(gqlReq)
+	variables := r.scrubValue(gqlReq.Variables)
+
+	if r.opts.Mode == ModeRecord {
+		return r.record(req, body, operation, variables, gqlReq.Query)
+	}
+	return r.replay(req, operation, variables, gqlReq.Query)
+}
+
+// record forwards the request and stores the exchange
+func (r *Recorder) record(req *http.Request, body []byte, operation string, variables interface{}, query string) (*http.Response, error) {
+	outgoing := req.Clone(req.Context())
+	outgoing.Body = io.NopCloser(bytes.NewReader(body))
+	outgoing.ContentLength = int64(len(body))
+
+	resp, err := r.opts.Transport.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	interaction := &Interaction{
+		Operation: operation,
+		Query:     query,
+		Header:    map[string]string{},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     map[string]string{"Content-Type": resp.Header.Get("Content-Type")},
+		},
+	}
+	if scrubbedBody, ok := r.scrubBody(respBody); ok {
+		interaction.Response.Body = scrubbedBody
+	} else {
+		interaction.Response.Text = string(respBody)
+	}
+	interaction.Variables, _ = variables.(map[string]interface{})
+	for _, name := range recordedHeaders {
+		if value := req.Header.Get(name); value != "" {
+			interaction.Header[name] = value
+		}
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		interaction.Response.Header["Retry-After"] = retryAfter
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// replay answers the request with the first unused matching interaction
+func (r *Recorder) replay(req *http.Request, operation string, variables interface{}, query string) (*http.Response, error) {
+	key := r.matchKey(req.Header.Get("X-Apito-Tenant-ID"), query, variables)
+
+	r.mu.Lock()
+	var match *Interaction
+	for _, interaction := range r.cassette.Interactions {
+		if !interaction.used && interaction.Operation == operation && r.matchKey(interaction.Header["X-Apito-Tenant-ID"], interaction.Query, interaction.Variables) == key {
+			match = interaction
+			break
+		}
+	}
+	if match != nil {
+		match.used = true
+	}
+	r.mu.Unlock()
+
+	if match == nil {
+		err := fmt.Errorf("%w: %s %s in %s", ErrNoInteraction, operation, key, r.path)
+		if r.opts.T != nil {
+			r.opts.T.Helper()
+			r.opts.T.Errorf("%v", err)
+		}
+		return nil, err
+	}
+
+	body := match.Response.body()
+	header := make(http.Header)
+	for name, value := range match.Response.Header {
+		header.Set(name, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.Response.StatusCode, http.StatusText(match.Response.StatusCode)),
+		StatusCode:    match.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Unused returns the recorded interactions not replayed so far
+func (r *Recorder) Unused() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []*Interaction
+	for _, interaction := range r.cassette.Interactions {
+		if !interaction.used {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// Close writes the cassette in record mode. It does nothing in replay mode.
+func (r *Recorder) Close() error {
+	if r.opts.Mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// matchKey returns the tenant, a hash of the whitespace-collapsed query and the canonical
+// JSON of the variables used for matching. encoding/json sorts map keys, which makes the
+// encoding independent of map order.
+func (r *Recorder) matchKey(tenantID, query string, variables interface{}) string {
+	m, _ := variables.(map[string]interface{})
+	filtered := make(map[string]interface{}, len(m))
+	for name, value := range m {
+		if _, ignored := r.ignore[name]; !ignored && value != nil {
+			filtered[name] = value
+		}
+	}
+	data, _ := json.Marshal(filtered)
+	hash := sha256.Sum256([]byte(strings.Join(strings.Fields(query), " ")))
+	return fmt.Sprintf("tenant=%q query=%s variables=%s", tenantID, hex.EncodeToString(hash[:8]), data)
+}
+
+// scrubValue returns a JSON-shaped copy of v with secret fields replaced
+func (r *Recorder) scrubValue(v interface{}) interface{} {
+	normalized, err := normalize(v)
+	if err != nil {
+		return nil
+	}
+	return r.scrubGeneric(normalized)
+}
+
+func (r *Recorder) scrubGeneric(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if _, ok := r.scrub[strings.ToLower(key)]; ok {
+				value[key] = scrubbed
+			} else {
+				value[key] = r.scrubGeneric(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = r.scrubGeneric(item)
+		}
+	}
+	return v
+}
+
+// scrubBody scrubs a JSON response body and reports false for other bodies
+func (r *Recorder) scrubBody(body []byte) (json.RawMessage, bool) {
+	var generic interface{}
+	if err := json.Unmarshal(body, &generic); err != nil {
+		return nil, false
+	}
+	data, err := json.Marshal(r.scrubGeneric(generic))
+	return data, err == nil
+}
@@ -0,0 +1,131 @@
+package apitotest_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	goapitosdk "github.com/apito-io/go-internal-sdk"
+	"github.com/apito-io/go-internal-sdk/apitotest"
+	"github.com/apito-io/types"
+)
+
+// exercise runs the same sequence of calls in record and replay mode
+func exercise(t *testing.T, client *goapitosdk.Client) {
+	t.Helper()
+	ctx := context.Background()
+
+	token, err := client.GenerateTenantToken(ctx, "base-token", "acme")
+	if err != nil || token == "" {
+		t.Fatalf("GenerateTenantToken failed: %v", err)
+	}
+
+	doc, err := client.CreateNewResource(ctx, &types.CreateAndUpdateRequest{
+		Model:   "product",
+		Payload: map[string]interface{}{"name": "Widget", "price": 10},
+	})
+	if err != nil {
+		t.Fatalf("CreateNewResource failed: %v", err)
+	}
+
+	// The same request twice gets the responses in recording order
+	for _, want := range []int{1, 0} {
+		count, err := client.Count(ctx, "product", goapitosdk.SearchOptions{})
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != want {
+			t.Errorf("Expected count %d, got %d", want, count)
+		}
+		if want == 1 {
+			if err := client.DeleteResource(ctx, "product", doc.ID); err != nil {
+				t.Fatalf("DeleteResource failed: %v", err)
+			}
+		}
+	}
+}
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "products.json")
+
+	server := apitotest.NewServer()
+	recorder, err := apitotest.NewRecorder(path, apitotest.RecorderOptions{Mode: apitotest.ModeRecord})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	exercise(t, goapitosdk.NewClient(goapitosdk.Config{BaseURL: server.URL, APIKey: "live-secret-key", HTTPClient: recorder.HTTPClient()}))
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cassette not written: %v", err)
+	}
+	for _, secret := range []string{"live-secret-key", "base-token", "eyJ"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Cassette contains secret %q", secret)
+		}
+	}
+
+	// The server is gone: every response must come from the cassette
+	replayer, err := apitotest.NewRecorder(path, apitotest.RecorderOptions{T: t})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	exercise(t, goapitosdk.NewClient(goapitosdk.Config{BaseURL: server.URL, APIKey: "other-key", HTTPClient: replayer.HTTPClient()}))
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("Expected every interaction to be replayed, %d left", len(unused))
+	}
+}
+
+func TestRecorderUnmatchedRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "count.json")
+
+	server := apitotest.NewServer()
+	defer server.Close()
+	recorder, err := apitotest.NewRecorder(path, apitotest.RecorderOptions{Mode: apitotest.ModeRecord})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	client := goapitosdk.NewClient(goapitosdk.Config{BaseURL: server.URL, APIKey: "key", HTTPClient: recorder.HTTPClient()})
+	if _, err := client.Count(goapitosdk.WithTenant(context.Background(), "acme"), "product", goapitosdk.SearchOptions{}); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	rt := &recordingT{}
+	replayer, err := apitotest.NewRecorder(path, apitotest.RecorderOptions{T: rt})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	client = goapitosdk.NewClient(goapitosdk.Config{BaseURL: "http://apito.invalid", APIKey: "key", HTTPClient: replayer.HTTPClient()})
+
+	// Other variables, another tenant and another query with the same operation name are all different requests
+	if _, err := client.Count(goapitosdk.WithTenant(context.Background(), "acme"), "user", goapitosdk.SearchOptions{}); !errors.Is(err, apitotest.ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction, got %v", err)
+	}
+	if _, err := client.Count(goapitosdk.WithTenant(context.Background(), "globex"), "product", goapitosdk.SearchOptions{}); !errors.Is(err, apitotest.ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction for another tenant, got %v", err)
+	}
+	if _, err := client.Search(goapitosdk.WithTenant(context.Background(), "acme"), "product", goapitosdk.SearchOptions{}); !errors.Is(err, apitotest.ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction for another query, got %v", err)
+	}
+	if len(rt.errors) != 3 || !strings.Contains(rt.errors[0], `"model":"user"`) || !strings.Contains(rt.errors[1], `tenant="globex"`) {
+		t.Errorf("Expected the unmatched requests to be reported, got %q", rt.errors)
+	}
+
+	if count, err := client.Count(goapitosdk.WithTenant(context.Background(), "acme"), "product", goapitosdk.SearchOptions{}); err != nil || count != 0 {
+		t.Errorf("Expected the recorded count, got %d (%v)", count, err)
+	}
+
+	if _, err := apitotest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), apitotest.RecorderOptions{}); err == nil {
+		t.Error("Expected an error for a missing cassette in replay mode")
+	}
+}
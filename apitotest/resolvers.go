@@ -0,0 +1,413 @@
+package apitotest
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// resolver computes the value of a root field. It is called with s.mu held.
+type resolver func(s *Server, tenantID string, args map[string]interface{}) (interface{}, error)
+
+// resolvers maps the root fields understood by the fake to their implementation
+var resolvers = map[string]resolver{
+	"getSingleData":           (*Server).getSingleData,
+	"getModelData":            (*Server).getModelData,
+	"upsertModelData":         (*Server).upsertModelData,
+	"deleteModelData":         (*Server).deleteModelData,
+	"restoreModelData":        (*Server).restoreModelData,
+	"updateModelDataStatus":   (*Server).updateModelDataStatus,
+	"scheduleModelDataStatus": (*Server).scheduleModelDataStatus,
+	"listModelDataRevisions":  (*Server).listModelDataRevisions,
+	"getModelDataRevision":    (*Server).getModelDataRevision,
+	"patchModelData":          (*Server).patchModelData,
+	"generateTenantToken":     (*Server).generateTenantToken,
+	"debug":                   (*Server).debug,
+}
+
+// resolve runs the resolver of a field. The caller must hold s.mu.
+func (s *Server) resolve(tenantID string, field rootField) (interface{}, error) {
+	resolve, ok := resolvers[field.Name]
+	if !ok {
+		return nil, &graphQLError{Message: fmt.Sprintf("unsupported field %q", field.Name), Code: "GRAPHQL_VALIDATION_FAILED"}
+	}
+
+	// Arguments are handled in their JSON form, as the real server receives them
+	args := make(map[string]interface{}, len(field.Args))
+	for name, value := range field.Args {
+		normalized, err := normalize(value)
+		if err != nil {
+			return nil, badInput("invalid argument %s: %v", name, err)
+		}
+		args[name] = normalized
+	}
+
+	return resolve(s, tenantID, args)
+}
+
+func badInput(format string, args ...interface{}) error {
+	return &graphQLError{Message: fmt.Sprintf(format, args...), Code: "BAD_USER_INPUT"}
+}
+
+func notFound(model, id string) error {
+	return &graphQLError{Message: fmt.Sprintf("document %q not found in model %q", id, model), Code: "NOT_FOUND"}
+}
+
+func (s *Server) getSingleData(tenantID string, args map[string]interface{}) (interface{}, error) {
+	model, _ := args["model"].(string)
+	id, _ := args["_id"].(string)
+	fields, _ := args["fields"].([]interface{})
+
+	if singlePage, _ := args["single_page_data"].(bool); singlePage && model != "" {
+		for _, doc := range s.documents[storeKey{tenantID, model}] {
+			if doc.Status != StatusTrashed {
+				return doc.response(fields), nil
+			}
+		}
+		return nil, notFound(model, id)
+	}
+
+	doc := s.find(tenantID, model, id)
+	if doc == nil {
+		return nil, notFound(model, id)
+	}
+	return doc.response(fields), nil
+}
+
+func (s *Server) getModelData(tenantID string, args map[string]interface{}) (interface{}, error) {
+	model, _ := args["model"].(string)
+	if model == "" {
+		return nil, badInput("model is required")
+	}
+
+	candidates := s.documents[storeKey{tenantID, model}]
+	if connection, ok := args["connection"].(map[string]interface{}); ok {
+		candidates = s.related(tenantID, candidates, connection)
+	}
+
+	status, _ := args["status"].(string)
+	search, _ := args["search"].(string)
+	filter, _ := args["where"].(map[string]interface{})
+
+	var matched []*Document
+	for _, doc := range candidates {
+		if status != "" && doc.Status != status || status == "" && doc.Status == StatusTrashed {
+			continue
+		}
+		if search != "" && !matchSearch(doc.Data, search) {
+			continue
+		}
+		if filter != nil {
+			ok, err := matchWhere(doc, filter)
+			if err != nil {
+				return nil, badInput("invalid where filter: %v", err)
+			}
+			if !ok {
+				continue
+			}
+		}
+		matched = append(matched, doc)
+	}
+
+	result := map[string]interface{}{"count": len(matched)}
+
+	if spec, ok := args["aggregate"].(map[string]interface{}); ok {
+		buckets, err := aggregate(matched, spec)
+		if err != nil {
+			return nil, badInput("%v", err)
+		}
+		result["aggregate"] = buckets
+	}
+
+	if order, ok := args["sort"].(map[string]interface{}); ok {
+		sortDocuments(matched, order)
+	}
+
+	// Without a limit every matching document is returned
+	page, _ := toFloat(args["page"])
+	limit, _ := toFloat(args["limit"])
+	if limit > 0 {
+		start := (max(int(page), 1) - 1) * int(limit)
+		end := start + int(limit)
+		matched = matched[min(start, len(matched)):min(end, len(matched))]
+	}
+
+	fields, _ := args["fields"].([]interface{})
+	results := make([]interface{}, len(matched))
+	for i, doc := range matched {
+		results[i] = doc.response(fields)
+	}
+	result["results"] = results
+
+	return result, nil
+}
+
+// related keeps the candidates connected to the document given by connection["_id"],
+// in either direction
+func (s *Server) related(tenantID string, candidates []*Document, connection map[string]interface{}) []*Document {
+	id, _ := connection["_id"].(string)
+	if id == "" {
+		id, _ = connection["id"].(string)
+	}
+	source := s.find(tenantID, "", id)
+
+	var result []*Document
+	for _, doc := range candidates {
+		if containsString(doc.Connections, id) || source != nil && containsString(source.Connections, doc.ID) {
+			result = append(result, doc)
+		}
+	}
+	return result
+}
+
+func (s *Server) upsertModelData(tenantID string, args map[string]interface{}) (interface{}, error) {
+	model, _ := args["model_name"].(string)
+	if model == "" {
+		return nil, badInput("model_name is required")
+	}
+	payload, ok := args["payload"].(map[string]interface{})
+	if !ok {
+		return nil, badInput("payload must be an object")
+	}
+
+	id, _ := args["_id"].(string)
+	var doc *Document
+	if id != "" {
+		if doc = s.find(tenantID, model, id); doc == nil {
+			return nil, notFound(model, id)
+		}
+	} else if singlePage, _ := args["single_page_data"].(bool); singlePage {
+		if docs := s.documents[storeKey{tenantID, model}]; len(docs) > 0 {
+			doc = docs[0]
+		}
+	}
+
+	if expected, _ := args["expected_revision"].(string); expected != "" && doc != nil && expected != doc.revision() {
+		return nil, &graphQLError{Message: fmt.Sprintf("document %q was modified since revision %s", doc.ID, expected), Code: "CONFLICT"}
+	}
+
+	expireAt, setExpiry, err := expiryArg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc == nil {
+		doc = s.create(tenantID, model, payload)
+		doc.ExpireAt = expireAt
+	} else {
+		if setExpiry {
+			doc.ExpireAt = expireAt
+		}
+		if force, _ := args["force_update"].(bool); force {
+			doc.Data = payload
+		} else {
+			for key, value := range payload {
+				doc.Data[key] = value
+			}
+		}
+		s.touch(doc)
+		s.saveRevision(doc)
+	}
+
+	for _, connected := range collectIDs(args["connect"]) {
+		if !containsString(doc.Connections, connected) {
+			doc.Connections = append(doc.Connections, connected)
+		}
+	}
+	for _, disconnected := range collectIDs(args["disconnect"]) {
+		for i, connected := range doc.Connections {
+			if connected == disconnected {
+				doc.Connections = append(doc.Connections[:i:i], doc.Connections[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return doc.response(nil), nil
+}
+
+// expiryArg parses the expire_at argument; an empty value clears the expiry
+func expiryArg(args map[string]interface{}) (time.Time, bool, error) {
+	value, ok := args["expire_at"]
+	if !ok || value == nil {
+		return time.Time{}, false, nil
+	}
+	raw, _ := value.(string)
+	if raw == "" {
+		return time.Time{}, true, nil
+	}
+	expireAt, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return time.Time{}, false, badInput("invalid expire_at %q: %v", raw, err)
+	}
+	return expireAt, true, nil
+}
+
+func (s *Server) deleteModelData(tenantID string, args map[string]interface{}) (interface{}, error) {
+	model, _ := args["model_name"].(string)
+	id, _ := args["_id"].(string)
+
+	doc := s.find(tenantID, model, id)
+	if doc == nil {
+		return nil, notFound(model, id)
+	}
+
+	if soft, _ := args["soft_delete"].(bool); soft {
+		if doc.Status != StatusTrashed {
+			doc.statusBeforeTrash = doc.Status
+			doc.Status = StatusTrashed
+			s.touch(doc)
+		}
+		return map[string]interface{}{"id": doc.ID, "status": StatusTrashed}, nil
+	}
+
+	s.remove(doc)
+	return map[string]interface{}{"id": doc.ID, "status": "deleted"}, nil
+}
+
+func (s *Server) restoreModelData(tenantID string, args map[string]interface{}) (interface{}, error) {
+	model, _ := args["model_name"].(string)
+	id, _ := args["_id"].(string)
+
+	doc := s.find(tenantID, model, id)
+	if doc == nil {
+		return nil, notFound(model, id)
+	}
+	if doc.Status != StatusTrashed {
+		return nil, badInput("document %q is not trashed", id)
+	}
+
+	doc.Status = doc.statusBeforeTrash
+	if doc.Status == "" {
+		doc.Status = StatusDraft
+	}
+	s.touch(doc)
+	return map[string]interface{}{"id": doc.ID, "status": doc.Status}, nil
+}
+
+func (s *Server) updateModelDataStatus(tenantID string, args map[string]interface{}) (interface{}, error) {
+	model, _ := args["model_name"].(string)
+	id, _ := args["_id"].(string)
+	status, _ := args["status"].(string)
+	if status == "" || status == StatusTrashed {
+		return nil, badInput("invalid status %q", status)
+	}
+
+	doc := s.find(tenantID, model, id)
+	if doc == nil {
+		return nil, notFound(model, id)
+	}
+	if doc.Status != status {
+		doc.Status = status
+		s.touch(doc)
+		s.saveRevision(doc)
+	}
+	return doc.response(nil), nil
+}
+
+func (s *Server) scheduleModelDataStatus(tenantID string, args map[string]interface{}) (interface{}, error) {
+	model, _ := args["model_name"].(string)
+	id, _ := args["_id"].(string)
+	status, _ := args["status"].(string)
+	if status == "" || status == StatusTrashed {
+		return nil, badInput("invalid status %q", status)
+	}
+	rawAt, _ := args["at"].(string)
+	at, err := time.Parse(time.RFC3339Nano, rawAt)
+	if err != nil {
+		return nil, badInput("invalid time %q: %v", rawAt, err)
+	}
+
+	doc := s.find(tenantID, model, id)
+	if doc == nil {
+		return nil, notFound(model, id)
+	}
+
+	// Transitions are kept in time order; past ones are applied before the next request
+	i := sort.Search(len(doc.transitions), func(i int) bool { return doc.transitions[i].at.After(at) })
+	doc.transitions = append(doc.transitions[:i:i], append([]transition{{status: status, at: at}}, doc.transitions[i:]...)...)
+	if !at.After(s.now()) {
+		s.advance()
+	}
+
+	return map[string]interface{}{"id": doc.ID, "status": status, "at": at.UTC().Format(time.RFC3339Nano)}, nil
+}
+
+func (s *Server) listModelDataRevisions(tenantID string, args map[string]interface{}) (interface{}, error) {
+	model, _ := args["model_name"].(string)
+	id, _ := args["_id"].(string)
+
+	doc := s.find(tenantID, model, id)
+	if doc == nil {
+		return nil, notFound(model, id)
+	}
+
+	revisions := make([]interface{}, len(doc.revisions))
+	for i, r := range doc.revisions {
+		revisions[i] = doc.revisionResponse(r)
+	}
+	return revisions, nil
+}
+
+func (s *Server) getModelDataRevision(tenantID string, args map[string]interface{}) (interface{}, error) {
+	model, _ := args["model_name"].(string)
+	id, _ := args["_id"].(string)
+	revisionID, _ := args["revision_id"].(string)
+
+	doc := s.find(tenantID, model, id)
+	if doc == nil {
+		return nil, notFound(model, id)
+	}
+	for _, r := range doc.revisions {
+		if r.id == revisionID {
+			return doc.revisionResponse(r), nil
+		}
+	}
+	return nil, &graphQLError{Message: fmt.Sprintf("revision %q of document %q not found", revisionID, id), Code: "NOT_FOUND"}
+}
+
+func (s *Server) generateTenantToken(tenantID string, args map[string]interface{}) (interface{}, error) {
+	if token, _ := args["token"].(string); token == "" {
+		return nil, &graphQLError{Message: "token is required", Code: "UNAUTHENTICATED"}
+	}
+	tenant, _ := args["tenant_id"].(string)
+	if tenant == "" {
+		return nil, badInput("tenant_id is required")
+	}
+	return map[string]interface{}{"token": s.mintToken(tenant)}, nil
+}
+
+func (s *Server) debug(tenantID string, args map[string]interface{}) (interface{}, error) {
+	stage, _ := args["stage"].(string)
+	return map[string]interface{}{"message": "debug: " + stage, "data": args["data"]}, nil
+}
+
+// collectIDs returns every string found in a connect or disconnect value
+func collectIDs(v interface{}) []string {
+	switch value := v.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var ids []string
+		for _, item := range value {
+			ids = append(ids, collectIDs(item)...)
+		}
+		return ids
+	case map[string]interface{}:
+		var ids []string
+		for _, item := range value {
+			ids = append(ids, collectIDs(item)...)
+		}
+		return ids
+	}
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
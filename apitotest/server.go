@@ -0,0 +1,609 @@
+// Package apitotest provides an in-process fake of the Apito GraphQL API for testing code
+// built on the SDK.
+//
+//	server := apitotest.NewServer()
+//	defer server.Close()
+//
+//	server.Seed("", "product", map[string]interface{}{"name": "Widget", "price": 10})
+//	client := goapitosdk.NewClient(goapitosdk.Config{BaseURL: server.URL, APIKey: "test"})
+//
+// The fake understands the operations sent by the SDK (getSingleData, getModelData,
+// upsertModelData, deleteModelData, restoreModelData, updateModelDataStatus,
+// scheduleModelDataStatus, listModelDataRevisions, getModelDataRevision, patchModelData,
+// generateTenantToken and debug), stores documents and their revisions in memory per
+// tenant and model, and applies where, search, sort and pagination semantics close to
+// the real server. Scheduled status changes are applied and documents past their
+// expire_at removed once the clock reaches them. Faults and latency can be injected to
+// exercise error handling and retries.
+//
+// MockClient covers unit tests that do not need HTTP at all: it implements
+// interfaces.InternalSDKOperation with programmable responses and call assertions.
+package apitotest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default document statuses
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+	StatusTrashed   = "trashed"
+)
+
+// Document is a document stored by the fake server
+type Document struct {
+	ID          string
+	Model       string
+	TenantID    string
+	Data        map[string]interface{}
+	Status      string // meta.status (default: StatusDraft)
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ExpireAt    time.Time // Zero when the document does not expire
+	Connections []string  // IDs of documents connected through connect
+
+	statusBeforeTrash string
+	revisions         []*savedRevision
+	transitions       []transition
+}
+
+// transition is a scheduled status change of a document
+type transition struct {
+	status string
+	at     time.Time
+}
+
+// savedRevision is a saved state of a document, recorded on every write of its data
+type savedRevision struct {
+	id     string
+	at     time.Time
+	status string
+	data   map[string]interface{}
+}
+
+// lookup returns the value at a dotted path of the data; "id" and "_id" refer to the document ID
+func (d *Document) lookup(path []string) interface{} {
+	if len(path) == 1 && (path[0] == "id" || path[0] == "_id") {
+		return d.ID
+	}
+	var current interface{} = d.Data
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+// clone returns a deep copy of the document
+func (d *Document) clone() *Document {
+	c := *d
+	c.Data = cloneMap(d.Data)
+	c.Connections = append([]string(nil), d.Connections...)
+	c.revisions = append([]*savedRevision(nil), d.revisions...)
+	c.transitions = append([]transition(nil), d.transitions...)
+	return &c
+}
+
+// response returns the document in the shape of the GraphQL API
+func (d *Document) response(fields []interface{}) map[string]interface{} {
+	data := d.Data
+	if len(fields) > 0 {
+		data = project(data, fields)
+	}
+	response := map[string]interface{}{
+		"id":   d.ID,
+		"type": d.Model,
+		"data": cloneMap(data),
+		"meta": map[string]interface{}{
+			"created_at": d.CreatedAt.UTC().Format(time.RFC3339Nano),
+			"updated_at": d.revision(),
+			"status":     d.Status,
+		},
+	}
+	if !d.ExpireAt.IsZero() {
+		response["expire_at"] = d.ExpireAt.UTC().Format(time.RFC3339Nano)
+	}
+	return response
+}
+
+// revision returns meta.updated_at, which identifies the state of the document for
+// expected_revision preconditions
+func (d *Document) revision() string {
+	return d.UpdatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// revisionResponse returns a revision of the document in the shape of the GraphQL API.
+// updated_at is the revision the document had once written, as for expected_revision.
+func (d *Document) revisionResponse(r *savedRevision) map[string]interface{} {
+	at := r.at.UTC().Format(time.RFC3339Nano)
+	return map[string]interface{}{
+		"id":   r.id,
+		"type": d.Model,
+		"data": cloneMap(r.data),
+		"meta": map[string]interface{}{
+			"created_at":       d.CreatedAt.UTC().Format(time.RFC3339Nano),
+			"updated_at":       at,
+			"status":           r.status,
+			"revision":         true,
+			"revision_at":      at,
+			"root_revision_id": d.ID,
+		},
+	}
+}
+
+func cloneMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	copied, _ := normalize(m)
+	result, _ := copied.(map[string]interface{})
+	return result
+}
+
+// Fault describes an error injected into matching requests
+type Fault struct {
+	Field      string        // Root field to fail, e.g. "getModelData"; empty matches every request
+	StatusCode int           // HTTP status to answer with; when 0 a GraphQL error is returned for the field
+	Message    string        // Error message (default: "injected fault")
+	Code       string        // extensions.code of the GraphQL error, e.g. "NOT_FOUND"
+	Header     http.Header   // Extra response headers, such as Retry-After
+	Latency    time.Duration // Delay before answering
+	Times      int           // Number of matching requests to fail (default: 1, negative: every request)
+}
+
+// Request is a request received by the fake server
+type Request struct {
+	OperationName string
+	Fields        []string // Root fields, in order
+	Query         string
+	Variables     map[string]interface{}
+	Header        http.Header
+	TenantID      string // Tenant the request was resolved to
+}
+
+// Server is a fake Apito GraphQL server. It is safe for concurrent use.
+type Server struct {
+	URL string // Base URL of the GraphQL endpoint, to be used as Config.BaseURL
+
+	server *httptest.Server
+
+	mu        sync.Mutex
+	apiKey    string
+	tokenTTL  time.Duration
+	latency   time.Duration
+	now       func() time.Time
+	nextID    int
+	documents map[storeKey][]*Document
+	tokens    map[string]issuedToken
+	faults    []*Fault
+	requests  []Request
+}
+
+// storeKey identifies the documents of one model of one tenant
+type storeKey struct {
+	tenantID string
+	model    string
+}
+
+// issuedToken is a tenant token minted by generateTenantToken
+type issuedToken struct {
+	tenantID  string
+	expiresAt time.Time
+}
+
+// NewServer starts a fake server. Close must be called when done.
+func NewServer() *Server {
+	s := &Server{
+		tokenTTL:  time.Hour,
+		now:       time.Now,
+		documents: make(map[storeKey][]*Document),
+		tokens:    make(map[string]issuedToken),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// RequireAPIKey makes the server reject requests whose X-Apito-Key differs from key with 401
+func (s *Server) RequireAPIKey(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = key
+}
+
+// SetTokenTTL sets the lifetime of tokens minted by generateTenantToken (default: 1 hour)
+func (s *Server) SetTokenTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokenTTL = ttl
+}
+
+// RevokeTokens invalidates every tenant token minted so far
+func (s *Server) RevokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = make(map[string]issuedToken)
+}
+
+// SetClock replaces the clock of the server (default: time.Now). It drives timestamps,
+// token and document expiry and scheduled status changes.
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// SetLatency delays every response by d
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// InjectFault fails the next matching requests as described by the fault
+func (s *Server) InjectFault(fault Fault) {
+	if fault.Times == 0 {
+		fault.Times = 1
+	}
+	if fault.Message == "" {
+		fault.Message = "injected fault"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// Seed stores a document and returns a copy of it
+func (s *Server) Seed(tenantID, model string, data map[string]interface{}) *Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.create(tenantID, model, cloneMap(data)).clone()
+}
+
+// Document returns a copy of a stored document
+func (s *Server) Document(tenantID, model, id string) (*Document, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+	if doc := s.find(tenantID, model, id); doc != nil {
+		return doc.clone(), true
+	}
+	return nil, false
+}
+
+// Documents returns copies of the documents of a model, in insertion order
+func (s *Server) Documents(tenantID, model string) []*Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+	docs := s.documents[storeKey{tenantID, model}]
+	result := make([]*Document, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.clone()
+	}
+	return result
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Reset removes every document, token, fault and recorded request
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.documents = make(map[storeKey][]*Document)
+	s.tokens = make(map[string]issuedToken)
+	s.faults = nil
+	s.requests = nil
+}
+
+// create stores a new document. The caller must hold s.mu.
+func (s *Server) create(tenantID, model string, data map[string]interface{}) *Document {
+	s.nextID++
+	now := s.now()
+	doc := &Document{
+		ID:        fmt.Sprintf("id-%d", s.nextID),
+		Model:     model,
+		TenantID:  tenantID,
+		Data:      data,
+		Status:    StatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if doc.Data == nil {
+		doc.Data = map[string]interface{}{}
+	}
+	key := storeKey{tenantID, model}
+	s.documents[key] = append(s.documents[key], doc)
+	s.saveRevision(doc)
+	return doc
+}
+
+// saveRevision records the current state of the document. The caller must hold s.mu.
+func (s *Server) saveRevision(doc *Document) {
+	s.nextID++
+	doc.revisions = append(doc.revisions, &savedRevision{
+		id:     fmt.Sprintf("rev-%d", s.nextID),
+		at:     doc.UpdatedAt,
+		status: doc.Status,
+		data:   cloneMap(doc.Data),
+	})
+}
+
+// touch records a change of the document. Timestamps strictly increase so that every
+// change yields a new revision. The caller must hold s.mu.
+func (s *Server) touch(doc *Document) {
+	now := s.now()
+	if !now.After(doc.UpdatedAt) {
+		now = doc.UpdatedAt.Add(time.Nanosecond)
+	}
+	doc.UpdatedAt = now
+}
+
+// advance applies the scheduled status changes that are due and removes expired documents.
+// The caller must hold s.mu.
+func (s *Server) advance() {
+	now := s.now()
+	for key, docs := range s.documents {
+		kept := docs[:0:0]
+		for _, doc := range docs {
+			if !doc.ExpireAt.IsZero() && !doc.ExpireAt.After(now) {
+				continue
+			}
+			kept = append(kept, doc)
+			for len(doc.transitions) > 0 && !doc.transitions[0].at.After(now) {
+				doc.Status = doc.transitions[0].status
+				doc.transitions = doc.transitions[1:]
+				s.touch(doc)
+				s.saveRevision(doc)
+			}
+		}
+		s.documents[key] = kept
+	}
+}
+
+// find returns a stored document; an empty model matches any model. The caller must hold s.mu.
+func (s *Server) find(tenantID, model, id string) *Document {
+	for key, docs := range s.documents {
+		if key.tenantID != tenantID || model != "" && key.model != model {
+			continue
+		}
+		for _, doc := range docs {
+			if doc.ID == id {
+				return doc
+			}
+		}
+	}
+	return nil
+}
+
+// remove deletes a stored document. The caller must hold s.mu.
+func (s *Server) remove(doc *Document) {
+	key := storeKey{doc.TenantID, doc.Model}
+	docs := s.documents[key]
+	for i, d := range docs {
+		if d == doc {
+			s.documents[key] = append(docs[:i:i], docs[i+1:]...)
+			return
+		}
+	}
+}
+
+// graphQLRequest is the body of a GraphQL request
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphQLError is an error reported in the errors array
+type graphQLError struct {
+	Message string
+	Code    string
+}
+
+func (e *graphQLError) Error() string {
+	return e.Message
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	fields, err := parseRootFields(req.Query, req.Variables)
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"errors": []interface{}{errorEntry(nil, &graphQLError{Message: err.Error(), Code: "GRAPHQL_PARSE_FAILED"})},
+		})
+		return
+	}
+
+	s.mu.Lock()
+	tenantID, status := s.authenticate(r)
+	record := Request{
+		OperationName: operationName(req),
+		Query:         req.Query,
+		Variables:     req.Variables,
+		Header:        r.Header.Clone(),
+		TenantID:      tenantID,
+	}
+	for _, field := range fields {
+		record.Fields = append(record.Fields, field.Name)
+	}
+	s.requests = append(s.requests, record)
+	latency := s.latency
+	fault := s.takeFault(fields)
+	s.mu.Unlock()
+
+	if fault != nil {
+		latency += fault.Latency
+	}
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	if fault != nil && fault.StatusCode != 0 {
+		for key, values := range fault.Header {
+			w.Header()[key] = values
+		}
+		http.Error(w, fault.Message, fault.StatusCode)
+		return
+	}
+
+	data := make(map[string]interface{}, len(fields))
+	var errs []interface{}
+
+	s.mu.Lock()
+	s.advance()
+	for _, field := range fields {
+		if fault != nil && (fault.Field == "" || fault.Field == field.Name) {
+			data[field.Alias] = nil
+			errs = append(errs, errorEntry([]interface{}{field.Alias}, &graphQLError{Message: fault.Message, Code: fault.Code}))
+			continue
+		}
+
+		value, err := s.resolve(tenantID, field)
+		if err != nil {
+			data[field.Alias] = nil
+			errs = append(errs, errorEntry([]interface{}{field.Alias}, err))
+			continue
+		}
+		data[field.Alias] = value
+	}
+	s.mu.Unlock()
+
+	body := map[string]interface{}{"data": data}
+	if len(errs) > 0 {
+		body["errors"] = errs
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// authenticate checks the API key and tenant token and returns the tenant of the request,
+// or the HTTP status to reject it with. The caller must hold s.mu.
+func (s *Server) authenticate(r *http.Request) (string, int) {
+	// A tenant token, shaped as a JWT, is sent in place of the API key or as a bearer token
+	key := r.Header.Get("X-Apito-Key")
+	bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok && strings.Count(key, ".") == 2 {
+		bearer, ok = key, true
+	} else if s.apiKey != "" && key != s.apiKey {
+		return "", http.StatusUnauthorized
+	}
+
+	tenantID := r.Header.Get("X-Apito-Tenant-ID")
+	if !ok {
+		return tenantID, 0
+	}
+
+	token, ok := s.tokens[bearer]
+	if !ok || !s.now().Before(token.expiresAt) {
+		return "", http.StatusUnauthorized
+	}
+	if tenantID != "" && tenantID != token.tenantID {
+		return "", http.StatusForbidden
+	}
+	return token.tenantID, 0
+}
+
+// takeFault returns the first fault matching one of the fields and consumes one use of it.
+// The caller must hold s.mu.
+func (s *Server) takeFault(fields []rootField) *Fault {
+	for i, fault := range s.faults {
+		matched := fault.Field == ""
+		for _, field := range fields {
+			matched = matched || field.Name == fault.Field
+		}
+		if !matched {
+			continue
+		}
+
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return fault
+	}
+	return nil
+}
+
+// mintToken returns an unsigned JWT carrying the tenant and expiry. The caller must hold s.mu.
+func (s *Server) mintToken(tenantID string) string {
+	s.nextID++
+	expiresAt := s.now().Add(s.tokenTTL)
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	claims, _ := json.Marshal(map[string]interface{}{"tenant_id": tenantID, "exp": expiresAt.Unix(), "jti": s.nextID})
+	token := header + "." + base64.RawURLEncoding.EncodeToString(claims) + "."
+
+	s.tokens[token] = issuedToken{tenantID: tenantID, expiresAt: expiresAt}
+	return token
+}
+
+func operationName(req graphQLRequest) string {
+	if req.OperationName != "" {
+		return req.OperationName
+	}
+	header := strings.TrimSpace(req.Query[:max(strings.IndexAny(req.Query, "({"), 0)])
+	if fields := strings.Fields(header); len(fields) == 2 {
+		return fields[1]
+	}
+	return ""
+}
+
+func errorEntry(path []interface{}, err error) map[string]interface{} {
+	entry := map[string]interface{}{"message": err.Error()}
+	if path != nil {
+		entry["path"] = path
+	}
+	if gqlErr, ok := err.(*graphQLError); ok && gqlErr.Code != "" {
+		entry["extensions"] = map[string]interface{}{"code": gqlErr.Code}
+	}
+	return entry
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,278 @@
+package apitotest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	goapitosdk "github.com/apito-io/go-internal-sdk"
+	"github.com/apito-io/go-internal-sdk/apitotest"
+	"github.com/apito-io/go-internal-sdk/where"
+	"github.com/apito-io/types"
+)
+
+func newTestClient(t *testing.T) (*apitotest.Server, *goapitosdk.Client) {
+	t.Helper()
+	server := apitotest.NewServer()
+	t.Cleanup(server.Close)
+	return server, goapitosdk.NewClient(goapitosdk.Config{BaseURL: server.URL, APIKey: "test-key"})
+}
+
+func TestCRUD(t *testing.T) {
+	server, client := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateNewResource(ctx, &types.CreateAndUpdateRequest{
+		Model:   "product",
+		Payload: map[string]interface{}{"name": "Widget", "price": 10},
+	})
+	if err != nil {
+		t.Fatalf("CreateNewResource failed: %v", err)
+	}
+
+	updated, err := client.UpdateResource(ctx, &types.CreateAndUpdateRequest{
+		ID:      created.ID,
+		Model:   "product",
+		Payload: map[string]interface{}{"price": 12},
+	})
+	if err != nil {
+		t.Fatalf("UpdateResource failed: %v", err)
+	}
+	if updated.Data["name"] != "Widget" || updated.Data["price"] != float64(12) {
+		t.Errorf("Expected a merged update, got %v", updated.Data)
+	}
+
+	doc, err := client.GetSingleResource(ctx, "product", created.ID, false)
+	if err != nil {
+		t.Fatalf("GetSingleResource failed: %v", err)
+	}
+	if doc.ID != created.ID || doc.Meta.Status != apitotest.StatusDraft {
+		t.Errorf("Unexpected document %+v", doc)
+	}
+
+	if err := client.DeleteResource(ctx, "product", created.ID); err != nil {
+		t.Fatalf("DeleteResource failed: %v", err)
+	}
+	if _, err := client.GetSingleResource(ctx, "product", created.ID, false); !errors.Is(err, goapitosdk.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if docs := server.Documents("", "product"); len(docs) != 0 {
+		t.Errorf("Expected the store to be empty, got %d documents", len(docs))
+	}
+}
+
+func TestSearchSemantics(t *testing.T) {
+	server, client := newTestClient(t)
+	for i, name := range []string{"Red Widget", "Blue Widget", "Gadget", "Red Gadget"} {
+		server.Seed("", "product", map[string]interface{}{
+			"name":     name,
+			"price":    10 * (i + 1),
+			"category": map[string]interface{}{"name": []string{"tools", "toys"}[i%2]},
+		})
+	}
+
+	result, err := client.Search(context.Background(), "product", goapitosdk.SearchOptions{
+		Where: where.Field("price").Gte(20).And(where.Field("category.name").Eq("tools")),
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if result.Count != 1 || result.Results[0].Data["name"] != "Gadget" {
+		t.Errorf("Expected only Gadget, got %+v", result.Results)
+	}
+
+	result, err = client.Search(context.Background(), "product", goapitosdk.SearchOptions{
+		Search: "red",
+		Sort:   map[string]int{"price": -1},
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if result.Count != 2 || result.Results[0].Data["name"] != "Red Gadget" {
+		t.Errorf("Expected red products by descending price, got %+v", result.Results)
+	}
+
+	result, err = client.Search(context.Background(), "product", goapitosdk.SearchOptions{
+		Where: where.Or(where.Field("name").StartsWith("Blue"), where.Field("price").In(40)),
+		Page:  2,
+		Limit: 1,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if result.Count != 2 || len(result.Results) != 1 || result.Results[0].Data["name"] != "Red Gadget" {
+		t.Errorf("Expected the second page of the OR filter, got %+v", result.Results)
+	}
+
+	var names []string
+	for doc, err := range client.IterateResources(context.Background(), "product", goapitosdk.IterateOptions{PageSize: 3}) {
+		if err != nil {
+			t.Fatalf("IterateResources failed: %v", err)
+		}
+		names = append(names, doc.Data["name"].(string))
+	}
+	if len(names) != 4 {
+		t.Errorf("Expected to iterate over 4 documents, got %v", names)
+	}
+}
+
+func TestTenantIsolationAndTokens(t *testing.T) {
+	server, client := newTestClient(t)
+	server.Seed("acme", "order", map[string]interface{}{"total": 5})
+	server.Seed("globex", "order", map[string]interface{}{"total": 7})
+
+	tenants := goapitosdk.NewTenantManager(client, goapitosdk.TenantManagerOptions{Token: "base"})
+	count, err := tenants.Client("acme").Count(context.Background(), "order", goapitosdk.SearchOptions{})
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 acme order, got %d", count)
+	}
+
+	if count, _ := client.Count(context.Background(), "order", goapitosdk.SearchOptions{}); count != 0 {
+		t.Errorf("Expected no orders outside of a tenant, got %d", count)
+	}
+
+	// Revoked tokens are refreshed transparently by the tenant manager
+	server.RevokeTokens()
+	if _, err := tenants.Client("globex").Count(context.Background(), "order", goapitosdk.SearchOptions{}); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if _, err := tenants.Client("acme").Count(context.Background(), "order", goapitosdk.SearchOptions{}); err != nil {
+		t.Errorf("Expected a fresh token after revocation, got %v", err)
+	}
+}
+
+func TestFaultInjection(t *testing.T) {
+	server, _ := newTestClient(t)
+	client := goapitosdk.NewClient(goapitosdk.Config{
+		BaseURL:     server.URL,
+		APIKey:      "test-key",
+		RetryPolicy: &goapitosdk.RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond},
+	})
+	server.Seed("", "product", map[string]interface{}{"name": "Widget"})
+
+	server.InjectFault(apitotest.Fault{Field: "getModelData", StatusCode: http.StatusServiceUnavailable, Times: 2})
+	if _, err := client.Count(context.Background(), "product", goapitosdk.SearchOptions{}); err != nil {
+		t.Errorf("Expected the retries to succeed, got %v", err)
+	}
+
+	server.InjectFault(apitotest.Fault{Field: "getModelData", Code: "FORBIDDEN", Message: "no access"})
+	if _, err := client.Count(context.Background(), "product", goapitosdk.SearchOptions{}); !errors.Is(err, goapitosdk.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+
+	server.SetLatency(50 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.Count(ctx, "product", goapitosdk.SearchOptions{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+
+	if requests := server.Requests(); len(requests) != 5 || requests[0].Fields[0] != "getModelData" {
+		t.Errorf("Expected 5 recorded getModelData requests, got %d", len(requests))
+	}
+}
+
+func TestBulkAndSoftDelete(t *testing.T) {
+	server, client := newTestClient(t)
+	server.RequireAPIKey("test-key")
+
+	requests := make([]*types.CreateAndUpdateRequest, 5)
+	for i := range requests {
+		requests[i] = &types.CreateAndUpdateRequest{Model: "task", Payload: map[string]interface{}{"done": i%2 == 0}}
+	}
+	if _, err := client.BulkCreate(context.Background(), requests, goapitosdk.BulkOptions{ChunkSize: 2}); err != nil {
+		t.Fatalf("BulkCreate failed: %v", err)
+	}
+
+	result, err := client.DeleteWhere(context.Background(), "task", where.Field("done").Eq(true), goapitosdk.DeleteWhereOptions{Soft: true, ExpectedCount: 3})
+	if err != nil {
+		t.Fatalf("DeleteWhere failed: %v", err)
+	}
+	if result.Matched != 3 {
+		t.Errorf("Expected 3 matches, got %d", result.Matched)
+	}
+
+	// Listing the trash filters on status, a server extension
+	extended := goapitosdk.NewClient(goapitosdk.Config{BaseURL: server.URL, APIKey: "test-key", ServerExtensions: true})
+	remaining, _ := client.Count(context.Background(), "task", goapitosdk.SearchOptions{})
+	trashed, _ := extended.Count(context.Background(), "task", goapitosdk.SearchOptions{Status: apitotest.StatusTrashed})
+	if remaining != 2 || trashed != 3 {
+		t.Errorf("Expected 2 remaining and 3 trashed, got %d and %d", remaining, trashed)
+	}
+
+	unauthorized := goapitosdk.NewClient(goapitosdk.Config{BaseURL: server.URL, APIKey: "wrong"})
+	if _, err := unauthorized.Count(context.Background(), "task", goapitosdk.SearchOptions{}); !errors.Is(err, goapitosdk.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	server, client := newTestClient(t)
+	for _, order := range []map[string]interface{}{
+		{"region": "eu", "total": 10},
+		{"region": "eu", "total": 30},
+		{"region": "us", "total": 5},
+	} {
+		server.Seed("", "order", order)
+	}
+
+	result, err := client.Aggregate(context.Background(), "order", goapitosdk.AggregateRequest{
+		GroupBy:      []string{"region"},
+		Aggregations: []goapitosdk.Aggregation{{Func: goapitosdk.AggSum, Field: "total"}, {Func: goapitosdk.AggMax, Field: "total"}},
+	})
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	if result.Count != 3 || len(result.Buckets) != 2 {
+		t.Fatalf("Expected 3 documents in 2 buckets, got %+v", result)
+	}
+	eu := result.Buckets[0]
+	if eu.Key["region"] != "eu" || eu.Count != 2 || eu.Values["sum_total"] != 40 || eu.Values["max_total"] != 30 {
+		t.Errorf("Unexpected eu bucket %+v", eu)
+	}
+}
+
+func TestPatchModelData(t *testing.T) {
+	server, client := newTestClient(t)
+	ctx := context.Background()
+
+	seeded := server.Seed("", "article", map[string]interface{}{
+		"title":  "Draft",
+		"tags":   []interface{}{"a"},
+		"author": map[string]interface{}{"name": "Kim", "email": "kim@example.com"},
+	})
+	serverPatch := goapitosdk.PatchOptions{ServerPatch: true}
+
+	if _, err := client.PatchResource(ctx, "article", seeded.ID, goapitosdk.MergePatch{
+		"author": map[string]interface{}{"email": nil},
+	}, serverPatch); err != nil {
+		t.Fatalf("PatchResource failed: %v", err)
+	}
+	if _, err := client.PatchResource(ctx, "article", seeded.ID, goapitosdk.JSONPatch{
+		{Op: "test", Path: "/title", Value: "Draft"},
+		{Op: "add", Path: "/tags/-", Value: "b"},
+		{Op: "replace", Path: "/title", Value: nil},
+	}, serverPatch); err != nil {
+		t.Fatalf("PatchResource failed: %v", err)
+	}
+	want := map[string]interface{}{"title": nil, "tags": []interface{}{"a", "b"}, "author": map[string]interface{}{"name": "Kim"}}
+	if current, _ := server.Document("", "article", seeded.ID); !reflect.DeepEqual(current.Data, want) {
+		t.Errorf("Unexpected document:\n got %v\nwant %v", current.Data, want)
+	}
+
+	if _, err := client.PatchResource(ctx, "article", seeded.ID, goapitosdk.JSONPatch{
+		{Op: "remove", Path: "/tags/0"},
+		{Op: "test", Path: "/title", Value: "Draft"},
+	}, serverPatch); !errors.Is(err, goapitosdk.ErrValidation) {
+		t.Errorf("Expected the failed test to be rejected, got %v", err)
+	}
+	if current, _ := server.Document("", "article", seeded.ID); !reflect.DeepEqual(current.Data, want) {
+		t.Errorf("Expected the document to be unchanged, got %v", current.Data)
+	}
+}
@@ -0,0 +1,331 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apito-io/go-internal-sdk/where"
+)
+
+// Errors returned by AuditSender.Send
+var (
+	ErrAuditBufferFull   = errors.New("audit log buffer is full")
+	ErrAuditSenderClosed = errors.New("audit log sender is closed")
+)
+
+// AuditData represents an audit log entry sent to Apito
+type AuditData struct {
+	Resource string                 `json:"resource"`
+	Action   string                 `json:"action"`
+	Author   map[string]interface{} `json:"author,omitempty"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+	Meta     map[string]interface{} `json:"meta,omitempty"`
+}
+
+// AuditLogEntry is an audit log entry stored by Apito
+type AuditLogEntry struct {
+	ID        string                 `json:"id"`
+	Resource  string                 `json:"resource"`
+	Action    string                 `json:"action"`
+	Author    map[string]interface{} `json:"author,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Meta      map[string]interface{} `json:"meta,omitempty"`
+	CreatedAt string                 `json:"created_at,omitempty"`
+}
+
+// AuditLogResult is a page of audit log entries
+type AuditLogResult struct {
+	Results []*AuditLogEntry `json:"results"`
+	Count   int              `json:"count"`
+}
+
+// AuditLogFilter selects audit log entries. Zero values are not filtered on.
+type AuditLogFilter struct {
+	Resource string    // Resource the entry refers to
+	Action   string    // Action performed, e.g. "create"
+	AuthorID string    // Matches author.user_id
+	From     time.Time // Entries created at or after
+	To       time.Time // Entries created at or before
+	Page     int
+	Limit    int
+}
+
+// SendAuditLog sends a single audit log entry
+func (c *Client) SendAuditLog(ctx context.Context, auditData AuditData) error {
+	return c.sendAuditLogs(ctx, []AuditData{auditData})
+}
+
+// sendAuditLogs sends audit log entries in a single request using aliased mutations
+func (c *Client) sendAuditLogs(ctx context.Context, entries []AuditData) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	declarations := make([]string, len(entries))
+	calls := make([]string, len(entries))
+	variables := make(map[string]interface{}, len(entries))
+	for i, entry := range entries {
+		if err := validateAuditData(entry); err != nil {
+			return err
+		}
+		declarations[i] = fmt.Sprintf("$audit_%d: JSON!", i)
+		calls[i] = fmt.Sprintf("\t\t\ta%d: sendAuditLog(audit: $audit_%d) {\n\t\t\t\tmessage\n\t\t\t}", i, i)
+		variables[fmt.Sprintf("audit_%d", i)] = entry
+	}
+
+	query := fmt.Sprintf("\n\t\tmutation SendAuditLog(%s) {\n%s\n\t\t}\n\t", strings.Join(declarations, ", "), strings.Join(calls, "\n"))
+	if _, err := c.executeGraphQL(ctx, query, variables); err != nil {
+		return fmt.Errorf("failed to send audit log: %w", err)
+	}
+
+	return nil
+}
+
+// validateAuditData checks the required fields of an audit log entry
+func validateAuditData(auditData AuditData) error {
+	if auditData.Resource == "" {
+		return newValidationError("audit log resource is required")
+	}
+	if auditData.Action == "" {
+		return newValidationError("audit log action is required")
+	}
+	return nil
+}
+
+// SearchAuditLogs searches audit log entries by resource, action, author and time range
+func (c *Client) SearchAuditLogs(ctx context.Context, filter AuditLogFilter) (*AuditLogResult, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, newValidationError("audit log time range ends before it starts")
+	}
+
+	var conditions []where.Expr
+	if filter.Resource != "" {
+		conditions = append(conditions, where.Field("resource").Eq(filter.Resource))
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, where.Field("action").Eq(filter.Action))
+	}
+	if filter.AuthorID != "" {
+		conditions = append(conditions, where.Field("author.user_id").Eq(filter.AuthorID))
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, where.Field("created_at").Gte(filter.From.UTC().Format(time.RFC3339)))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, where.Field("created_at").Lte(filter.To.UTC().Format(time.RFC3339)))
+	}
+
+	query := `
+		query GetAuditLogs($page: Int, $limit: Int, $where: JSON) {
+			getAuditLogs(page: $page, limit: $limit, where: $where) {
+				results {
+					id
+					resource
+					action
+					author
+					data
+					meta
+					created_at
+				}
+				count
+			}
+		}
+	`
+
+	variables := map[string]interface{}{}
+	if expr := where.And(conditions...); !expr.IsEmpty() {
+		variables["where"] = expr
+	}
+	if filter.Page > 0 {
+		variables["page"] = filter.Page
+	}
+	if filter.Limit > 0 {
+		variables["limit"] = filter.Limit
+	}
+
+	var result *AuditLogResult
+	response, err := c.executeGraphQLInto(ctx, query, variables, map[string]interface{}{"getAuditLogs": &result})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search audit logs: %w", err)
+	}
+
+	if err := decodeResponseField(response, "getAuditLogs", &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AuditSenderOptions configures an AuditSender
+type AuditSenderOptions struct {
+	BufferSize    int                                  // Entries queued before Send reports ErrAuditBufferFull (default: 1000)
+	BatchSize     int                                  // Entries sent per request (default: 50)
+	FlushInterval time.Duration                        // Maximum time an entry waits in the queue (default: 2 seconds)
+	SendTimeout   time.Duration                        // Timeout of each batch request (default: 30 seconds)
+	OnError       func(err error, entries []AuditData) // Called when a batch cannot be sent (optional)
+}
+
+// AuditSender sends audit log entries asynchronously in batches.
+// Entries still queued are sent when Close is called.
+type AuditSender struct {
+	client  *Client
+	ctx     context.Context // Values of the context given to NewAuditSender, without its cancellation
+	opts    AuditSenderOptions
+	entries chan AuditData
+	flushes chan chan error
+	quit    chan struct{}
+	done    chan struct{}
+
+	mu       sync.RWMutex
+	closed   bool
+	closeErr error // Error of the final flush, set before done is closed
+}
+
+// NewAuditSender starts an asynchronous, buffered audit log sender. Batches are sent with the
+// values of ctx, such as its tenant, API key and headers, but outlive its cancellation.
+func (c *Client) NewAuditSender(ctx context.Context, opts AuditSenderOptions) *AuditSender {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 1000
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 50
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 2 * time.Second
+	}
+	if opts.SendTimeout <= 0 {
+		opts.SendTimeout = 30 * time.Second
+	}
+
+	s := &AuditSender{
+		client:  c,
+		ctx:     context.WithoutCancel(ctx),
+		opts:    opts,
+		entries: make(chan AuditData, opts.BufferSize),
+		flushes: make(chan chan error),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Send queues an entry without blocking. An entry without resource or action is rejected
+// right away so that it cannot fail the batch it would be sent with.
+func (s *AuditSender) Send(auditData AuditData) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return ErrAuditSenderClosed
+	}
+	if err := validateAuditData(auditData); err != nil {
+		return err
+	}
+
+	select {
+	case s.entries <- auditData:
+		return nil
+	default:
+		return ErrAuditBufferFull
+	}
+}
+
+// Flush sends every queued entry and waits for the requests to complete
+func (s *AuditSender) Flush(ctx context.Context) error {
+	result := make(chan error, 1)
+	select {
+	case s.flushes <- result:
+	case <-s.done:
+		return ErrAuditSenderClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting entries, sends the queued ones and waits for completion.
+// It returns the error of the final flush, if any.
+func (s *AuditSender) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		<-s.done
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.quit)
+	<-s.done
+	return s.closeErr
+}
+
+// run batches queued entries until the sender is closed
+func (s *AuditSender) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]AuditData, 0, s.opts.BatchSize)
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		entries := batch
+		batch = make([]AuditData, 0, s.opts.BatchSize)
+
+		ctx, cancel := context.WithTimeout(s.ctx, s.opts.SendTimeout)
+		defer cancel()
+		err := s.client.sendAuditLogs(ctx, entries)
+		if err != nil && s.opts.OnError != nil {
+			s.opts.OnError(err, entries)
+		}
+		return err
+	}
+
+	// drain moves every queued entry into batches, sending full ones
+	drain := func() error {
+		var errs []error
+		for {
+			select {
+			case entry := <-s.entries:
+				batch = append(batch, entry)
+				if len(batch) >= s.opts.BatchSize {
+					errs = append(errs, send())
+				}
+			default:
+				errs = append(errs, send())
+				return errors.Join(errs...)
+			}
+		}
+	}
+
+	for {
+		select {
+		case entry := <-s.entries:
+			batch = append(batch, entry)
+			if len(batch) >= s.opts.BatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case result := <-s.flushes:
+			result <- drain()
+		case <-s.quit:
+			s.closeErr = drain()
+			return
+		}
+	}
+}
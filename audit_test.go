@@ -0,0 +1,173 @@
+package goapitosdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newAuditServer counts the audit entries received per request
+func newAuditServer(t *testing.T, batches *[]int) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req capturedRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		*batches = append(*batches, len(req.Variables))
+		mu.Unlock()
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSendAuditLog(t *testing.T) {
+	var requests []capturedRequest
+	server := newCapturingServer(t, `{"data":{"a0":{"message":"ok"}}}`, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	err := client.SendAuditLog(context.Background(), AuditData{
+		Resource: "users",
+		Action:   "create",
+		Author:   map[string]interface{}{"user_id": "admin-123"},
+	})
+	if err != nil {
+		t.Fatalf("SendAuditLog failed: %v", err)
+	}
+
+	audit, _ := requests[0].Variables["audit_0"].(map[string]interface{})
+	if audit["resource"] != "users" || audit["action"] != "create" {
+		t.Errorf("Unexpected audit payload: %v", requests[0].Variables)
+	}
+
+	if err := client.SendAuditLog(context.Background(), AuditData{Resource: "users"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected validation error for missing action, got %v", err)
+	}
+}
+
+func TestAuditSenderBatchesAndFlushesOnClose(t *testing.T) {
+	var batches []int
+	server := newAuditServer(t, &batches)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	sender := client.NewAuditSender(context.Background(), AuditSenderOptions{BatchSize: 4, FlushInterval: time.Hour})
+	for i := 0; i < 10; i++ {
+		if err := sender.Send(AuditData{Resource: "task", Action: "update"}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		// An invalid entry is rejected without failing the batch of the others
+		if i == 5 {
+			if err := sender.Send(AuditData{Resource: "task"}); !errors.Is(err, ErrValidation) {
+				t.Errorf("Expected a validation error for an entry without action, got %v", err)
+			}
+		}
+	}
+	if err := sender.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	total := 0
+	for _, n := range batches {
+		if n > 4 {
+			t.Errorf("Expected batches of at most 4 entries, got %d", n)
+		}
+		total += n
+	}
+	if total != 10 {
+		t.Errorf("Expected 10 entries to be sent, got %d in %v", total, batches)
+	}
+
+	if err := sender.Send(AuditData{Resource: "task", Action: "update"}); !errors.Is(err, ErrAuditSenderClosed) {
+		t.Errorf("Expected ErrAuditSenderClosed, got %v", err)
+	}
+}
+
+func TestAuditSenderFlushAndErrors(t *testing.T) {
+	server := newStaticServer(t, http.StatusInternalServerError, "down")
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	var failed int
+	sender := client.NewAuditSender(context.Background(), AuditSenderOptions{
+		BufferSize:    2,
+		FlushInterval: time.Hour,
+		OnError:       func(err error, entries []AuditData) { failed += len(entries) },
+	})
+	defer sender.Close()
+
+	sender.Send(AuditData{Resource: "task", Action: "a"})
+	sender.Send(AuditData{Resource: "task", Action: "b"})
+	if err := sender.Send(AuditData{Resource: "task", Action: "c"}); !errors.Is(err, ErrAuditBufferFull) {
+		t.Errorf("Expected ErrAuditBufferFull, got %v", err)
+	}
+
+	if err := sender.Flush(context.Background()); err == nil {
+		t.Error("Expected flush to report the failed batch")
+	}
+	if failed != 2 {
+		t.Errorf("Expected OnError to see 2 entries, got %d", failed)
+	}
+}
+
+func TestSearchAuditLogs(t *testing.T) {
+	var requests []capturedRequest
+	server := newCapturingServer(t, `{"data":{"getAuditLogs":{"results":[{"id":"1","resource":"users","action":"create"}],"count":1}}}`, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	result, err := client.SearchAuditLogs(context.Background(), AuditLogFilter{
+		Resource: "users",
+		AuthorID: "admin-123",
+		From:     from,
+		To:       from.Add(24 * time.Hour),
+		Limit:    20,
+	})
+	if err != nil {
+		t.Fatalf("SearchAuditLogs failed: %v", err)
+	}
+	if result.Count != 1 || result.Results[0].Action != "create" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	whereJSON, _ := json.Marshal(requests[0].Variables["where"])
+	for _, fragment := range []string{`"resource":{"eq":"users"}`, `"author":{"user_id":{"eq":"admin-123"}}`, `"gte":"2024-01-01T00:00:00Z"`} {
+		if !strings.Contains(string(whereJSON), fragment) {
+			t.Errorf("Expected where to contain %s, got %s", fragment, whereJSON)
+		}
+	}
+
+	_, err = client.SearchAuditLogs(context.Background(), AuditLogFilter{From: from, To: from.Add(-time.Hour)})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected validation error for inverted range, got %v", err)
+	}
+}
+
+func TestAuditSenderKeepsContextValues(t *testing.T) {
+	tenants := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenants <- r.Header.Get("X-Apito-Tenant-ID")
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	ctx, cancel := context.WithCancel(WithTenant(context.Background(), "acme"))
+	sender := client.NewAuditSender(ctx, AuditSenderOptions{FlushInterval: time.Hour})
+	cancel()
+
+	if err := sender.Send(AuditData{Resource: "task", Action: "update"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if err := sender.Close(); err != nil {
+		t.Fatalf("Expected the batch to be sent after the context was canceled, got %v", err)
+	}
+	if tenant := <-tenants; tenant != "acme" {
+		t.Errorf("Expected the batch to be sent for tenant acme, got %q", tenant)
+	}
+}
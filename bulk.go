@@ -0,0 +1,402 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/apito-io/types"
+)
+
+// BulkOptions configures how bulk operations are split into requests
+type BulkOptions struct {
+	ChunkSize   int // Mutations packed into one HTTP request using GraphQL aliases (default: 50)
+	Concurrency int // Chunks sent in parallel (default: 4)
+}
+
+// BulkItemResult reports the outcome of one item of a bulk operation
+type BulkItemResult struct {
+	Index    int                             // Position of the item in the input slice
+	ID       string                          // Document ID, when known
+	Document *types.DefaultDocumentStructure // Document returned by the server, nil on failure
+	Err      error                           // Failure of this item, nil on success
+}
+
+// BulkResult is the per-item report of a bulk operation, in input order
+type BulkResult struct {
+	Items     []BulkItemResult
+	Succeeded int
+	Failed    int
+}
+
+// TypedBulkItemResult reports the outcome of one item of a typed bulk operation
+type TypedBulkItemResult[T any] struct {
+	Index    int
+	ID       string
+	Document *types.TypedDocumentStructure[T]
+	Err      error
+}
+
+// TypedBulkResult is the per-item report of a typed bulk operation, in input order
+type TypedBulkResult[T any] struct {
+	Items     []TypedBulkItemResult[T]
+	Succeeded int
+	Failed    int
+}
+
+// BulkError is returned alongside the report when at least one item failed
+type BulkError struct {
+	Total  int
+	Failed int
+	Errs   []error // Errors of the failed items, in input order
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("bulk operation failed for %d of %d items: %v", e.Failed, e.Total, e.Errs[0])
+}
+
+func (e *BulkError) Unwrap() []error {
+	return e.Errs
+}
+
+// bulkItem is one aliased mutation of a bulk request
+type bulkItem struct {
+	declarations []string               // Variable declarations, already suffixed
+	call         string                 // Aliased mutation call
+	variables    map[string]interface{} // Suffixed variables
+	id           string                 // Document ID, when known up front
+}
+
+// bulkItemBuilder builds the aliased mutation for the item at index i
+type bulkItemBuilder func(i int, alias string) (*bulkItem, error)
+
+// documentSelection is the selection set returned by bulk create and update mutations
+const documentSelection = `{
+					id
+					type
+					data
+					expire_at
+					meta {
+						created_at
+						updated_at
+						status
+						revision
+						revision_at
+					}
+				}`
+
+// BulkCreate creates many resources, packing several mutations per request.
+// The returned report always covers every request; the error is a *BulkError when any item failed.
+func (c *Client) BulkCreate(ctx context.Context, requests []*types.CreateAndUpdateRequest, opts BulkOptions) (*BulkResult, error) {
+	return c.runBulk(ctx, "BulkCreate", len(requests), opts, bulkCreateBuilder(requests))
+}
+
+// bulkCreateBuilder builds the aliased upsert creating each request
+func bulkCreateBuilder(requests []*types.CreateAndUpdateRequest) bulkItemBuilder {
+	return func(i int, alias string) (*bulkItem, error) {
+		request := requests[i]
+		if request == nil || request.Model == "" {
+			return nil, newValidationError("model is required")
+		}
+		if request.Payload == nil {
+			return nil, newValidationError("payload is required")
+		}
+
+		item := &bulkItem{
+			declarations: []string{
+				fmt.Sprintf("$model_%d: String!", i), fmt.Sprintf("$payload_%d: JSON!", i),
+				fmt.Sprintf("$single_page_data_%d: Boolean", i), fmt.Sprintf("$connect_%d: JSON", i),
+			},
+			call: fmt.Sprintf("%s: upsertModelData(connect: $connect_%d, model_name: $model_%d, single_page_data: $single_page_data_%d, payload: $payload_%d) %s",
+				alias, i, i, i, i, documentSelection),
+			variables: map[string]interface{}{
+				fmt.Sprintf("model_%d", i):            request.Model,
+				fmt.Sprintf("payload_%d", i):          request.Payload,
+				fmt.Sprintf("single_page_data_%d", i): request.SinglePageData,
+			},
+		}
+		if request.Connect != nil {
+			item.variables[fmt.Sprintf("connect_%d", i)] = request.Connect
+		}
+		return item, nil
+	}
+}
+
+// BulkUpdate updates many resources, packing several mutations per request.
+// The returned report always covers every request; the error is a *BulkError when any item failed.
+func (c *Client) BulkUpdate(ctx context.Context, requests []*types.CreateAndUpdateRequest, opts BulkOptions) (*BulkResult, error) {
+	return c.runBulk(ctx, "BulkUpdate", len(requests), opts, bulkUpdateBuilder(requests))
+}
+
+// bulkUpdateBuilder builds the aliased upsert updating each request
+func bulkUpdateBuilder(requests []*types.CreateAndUpdateRequest) bulkItemBuilder {
+	return func(i int, alias string) (*bulkItem, error) {
+		request := requests[i]
+		if request == nil || request.ID == "" {
+			return nil, newValidationError("id is required")
+		}
+		if request.Model == "" {
+			return nil, newValidationError("model is required")
+		}
+		if request.Payload == nil {
+			return nil, newValidationError("payload is required")
+		}
+
+		item := &bulkItem{
+			id: request.ID,
+			declarations: []string{
+				fmt.Sprintf("$_id_%d: String!", i), fmt.Sprintf("$model_%d: String!", i), fmt.Sprintf("$payload_%d: JSON!", i),
+				fmt.Sprintf("$single_page_data_%d: Boolean", i), fmt.Sprintf("$force_update_%d: Boolean", i),
+				fmt.Sprintf("$connect_%d: JSON", i), fmt.Sprintf("$disconnect_%d: JSON", i),
+			},
+			call: fmt.Sprintf("%s: upsertModelData(connect: $connect_%d, model_name: $model_%d, single_page_data: $single_page_data_%d, force_update: $force_update_%d, disconnect: $disconnect_%d, _id: $_id_%d, payload: $payload_%d) %s",
+				alias, i, i, i, i, i, i, i, documentSelection),
+			variables: map[string]interface{}{
+				fmt.Sprintf("_id_%d", i):              request.ID,
+				fmt.Sprintf("model_%d", i):            request.Model,
+				fmt.Sprintf("payload_%d", i):          request.Payload,
+				fmt.Sprintf("single_page_data_%d", i): request.SinglePageData,
+				fmt.Sprintf("force_update_%d", i):     request.ForceUpdate,
+			},
+		}
+		if request.Connect != nil {
+			item.variables[fmt.Sprintf("connect_%d", i)] = request.Connect
+		}
+		if request.Disconnect != nil {
+			item.variables[fmt.Sprintf("disconnect_%d", i)] = request.Disconnect
+		}
+		return item, nil
+	}
+}
+
+// BulkDelete deletes many resources of a model, packing several mutations per request.
+// The returned report always covers every ID; the error is a *BulkError when any item failed.
+func (c *Client) BulkDelete(ctx context.Context, model string, ids []string, opts BulkOptions) (*BulkResult, error) {
+	return c.bulkDelete(ctx, model, ids, false, opts)
+}
+
+// bulkDelete deletes many resources, moving them to the trash when soft is set
+func (c *Client) bulkDelete(ctx context.Context, model string, ids []string, soft bool, opts BulkOptions) (*BulkResult, error) {
+	return c.runBulk(ctx, "BulkDelete", len(ids), opts, func(i int, alias string) (*bulkItem, error) {
+		if model == "" {
+			return nil, newValidationError("model is required")
+		}
+		if ids[i] == "" {
+			return nil, newValidationError("id is required")
+		}
+
+		item := &bulkItem{
+			id:           ids[i],
+			declarations: []string{fmt.Sprintf("$model_%d: String!", i), fmt.Sprintf("$_id_%d: String!", i)},
+			call:         fmt.Sprintf("%s: deleteModelData(model_name: $model_%d, _id: $_id_%d) %s", alias, i, i, deleteSelection),
+			variables: map[string]interface{}{
+				fmt.Sprintf("model_%d", i): model,
+				fmt.Sprintf("_id_%d", i):   ids[i],
+			},
+		}
+		if soft {
+			item.declarations = append(item.declarations, fmt.Sprintf("$soft_delete_%d: Boolean", i))
+			item.call = fmt.Sprintf("%s: deleteModelData(model_name: $model_%d, _id: $_id_%d, soft_delete: $soft_delete_%d) %s", alias, i, i, i, deleteSelection)
+			item.variables[fmt.Sprintf("soft_delete_%d", i)] = true
+		}
+		return item, nil
+	})
+}
+
+// BulkCreateTyped creates many resources and returns typed documents
+func BulkCreateTyped[T any](c *Client, ctx context.Context, requests []*types.CreateAndUpdateRequest, opts BulkOptions) (*TypedBulkResult[T], error) {
+	return runBulkTyped[T](c, ctx, "BulkCreate", len(requests), opts, bulkCreateBuilder(requests))
+}
+
+// BulkUpdateTyped updates many resources and returns typed documents
+func BulkUpdateTyped[T any](c *Client, ctx context.Context, requests []*types.CreateAndUpdateRequest, opts BulkOptions) (*TypedBulkResult[T], error) {
+	return runBulkTyped[T](c, ctx, "BulkUpdate", len(requests), opts, bulkUpdateBuilder(requests))
+}
+
+// bulkOutcome is the outcome of one item of a bulk operation, with the document decoded into D
+type bulkOutcome[D any] struct {
+	id       string
+	document *D
+	err      error
+}
+
+// runBulk runs a bulk operation and reports its items as raw documents
+func (c *Client) runBulk(ctx context.Context, name string, n int, opts BulkOptions, build bulkItemBuilder) (*BulkResult, error) {
+	outcomes := runBulkItems[types.DefaultDocumentStructure](c, ctx, name, n, opts, build)
+
+	result := &BulkResult{Items: make([]BulkItemResult, n)}
+	var errs []error
+	for i, outcome := range outcomes {
+		item := BulkItemResult{Index: i, ID: outcome.id, Document: outcome.document, Err: outcome.err}
+		if item.Document != nil && item.Document.ID != "" {
+			item.ID = item.Document.ID
+		}
+		if item.Err != nil {
+			result.Failed++
+			errs = append(errs, item.Err)
+		} else {
+			result.Succeeded++
+		}
+		result.Items[i] = item
+	}
+	if len(errs) > 0 {
+		return result, &BulkError{Total: n, Failed: result.Failed, Errs: errs}
+	}
+	return result, nil
+}
+
+// runBulkTyped runs a bulk operation and reports its items as typed documents
+func runBulkTyped[T any](c *Client, ctx context.Context, name string, n int, opts BulkOptions, build bulkItemBuilder) (*TypedBulkResult[T], error) {
+	outcomes := runBulkItems[typedDocument[T]](c, ctx, name, n, opts, build)
+
+	result := &TypedBulkResult[T]{Items: make([]TypedBulkItemResult[T], n)}
+	var errs []error
+	for i, outcome := range outcomes {
+		item := TypedBulkItemResult[T]{Index: i, ID: outcome.id, Err: outcome.err}
+		if outcome.document != nil {
+			item.Document = outcome.document.typed()
+			if item.Document.ID != "" {
+				item.ID = item.Document.ID
+			}
+		}
+		if item.Err != nil {
+			result.Failed++
+			errs = append(errs, item.Err)
+		} else {
+			result.Succeeded++
+		}
+		result.Items[i] = item
+	}
+	if len(errs) > 0 {
+		return result, &BulkError{Total: n, Failed: result.Failed, Errs: errs}
+	}
+	return result, nil
+}
+
+// runBulkItems builds aliased mutations for n items and sends them in chunks with bounded concurrency
+func runBulkItems[D any](c *Client, ctx context.Context, name string, n int, opts BulkOptions, build bulkItemBuilder) []bulkOutcome[D] {
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = 50
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	outcomes := make([]bulkOutcome[D], n)
+	items := make([]*bulkItem, n)
+	var pending []int
+	for i := 0; i < n; i++ {
+		item, err := build(i, fmt.Sprintf("m%d", i))
+		if err != nil {
+			outcomes[i].err = err
+			continue
+		}
+		items[i] = item
+		outcomes[i].id = item.id
+		pending = append(pending, i)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for start := 0; start < len(pending); start += chunkSize {
+		chunk := pending[start:min(start+chunkSize, len(pending))]
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for _, i := range chunk {
+				outcomes[i].err = ctx.Err()
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(chunk []int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			sendBulkChunk(c, ctx, name, chunk, items, outcomes)
+		}(chunk)
+	}
+	wg.Wait()
+
+	return outcomes
+}
+
+// sendBulkChunk sends one aliased mutation and fills in the results of its items.
+// Each goroutine writes to distinct indexes of results.
+func sendBulkChunk[D any](c *Client, ctx context.Context, name string, chunk []int, items []*bulkItem, results []bulkOutcome[D]) {
+	var declarations, calls []string
+	variables := make(map[string]interface{})
+	documents := make([]*D, len(chunk))
+	targets := make(map[string]interface{}, len(chunk))
+	for j, i := range chunk {
+		declarations = append(declarations, items[i].declarations...)
+		calls = append(calls, "\t\t\t"+items[i].call)
+		for k, v := range items[i].variables {
+			variables[k] = v
+		}
+		targets[fmt.Sprintf("m%d", i)] = &documents[j]
+	}
+
+	query := fmt.Sprintf("\n\t\tmutation %s(%s) {\n%s\n\t\t}\n\t", name, strings.Join(declarations, ", "), strings.Join(calls, "\n"))
+	response, err := c.executeGraphQLInto(ctx, query, variables, targets)
+
+	// Errors attributed to an alias through their path fail only that item
+	itemErrs := make(map[string]error)
+	var gqlErrs GraphQLErrors
+	if errors.As(err, &gqlErrs) && response != nil {
+		for _, gqlErr := range gqlErrs {
+			if len(gqlErr.Path) > 0 {
+				if alias, ok := gqlErr.Path[0].(string); ok {
+					itemErrs[alias] = errors.Join(itemErrs[alias], gqlErr)
+					continue
+				}
+			}
+			// An error without a path cannot be attributed, fail the whole chunk
+			itemErrs = nil
+			break
+		}
+		if itemErrs != nil {
+			err = nil
+		}
+	}
+
+	data, _ := responseData(response)
+	for j, i := range chunk {
+		alias := fmt.Sprintf("m%d", i)
+		if err != nil {
+			results[i].err = fmt.Errorf("failed to execute bulk mutation: %w", err)
+			continue
+		}
+		if itemErr := itemErrs[alias]; itemErr != nil {
+			results[i].err = itemErr
+			continue
+		}
+
+		raw, ok := data[alias]
+		if !ok || raw == nil {
+			results[i].err = fmt.Errorf("%s returned null: %w", alias, ErrNotFound)
+			continue
+		}
+
+		document := documents[j]
+		if err := decodeResponseField(response, alias, &document); err != nil {
+			results[i].err = err
+			continue
+		}
+		results[i].document = document
+	}
+}
+
+// responseData returns the data object of a GraphQL response
+func responseData(response *types.GraphQLResponse) (map[string]interface{}, bool) {
+	if response == nil {
+		return nil, false
+	}
+	data, ok := response.Data.(map[string]interface{})
+	return data, ok
+}
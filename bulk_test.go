@@ -0,0 +1,151 @@
+package goapitosdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apito-io/types"
+)
+
+// newBulkServer answers aliased mutations, failing the items whose payload name is "bad"
+func newBulkServer(t *testing.T, requests *int32, maxInFlight *int32) *httptest.Server {
+	t.Helper()
+	var inFlight int32
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		mu.Lock()
+		if current > *maxInFlight {
+			*maxInFlight = current
+		}
+		mu.Unlock()
+
+		var req capturedRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		data := map[string]interface{}{}
+		var errs []map[string]interface{}
+		for key, value := range req.Variables {
+			if !strings.HasPrefix(key, "model_") {
+				continue
+			}
+			index := strings.TrimPrefix(key, "model_")
+			alias := "m" + index
+			payload, _ := req.Variables["payload_"+index].(map[string]interface{})
+			if payload != nil && payload["name"] == "bad" {
+				data[alias] = nil
+				errs = append(errs, map[string]interface{}{"message": "invalid payload", "path": []string{alias}})
+				continue
+			}
+			id, _ := req.Variables["_id_"+index].(string)
+			if id == "" {
+				id = "new-" + index
+			}
+			data[alias] = map[string]interface{}{"id": id, "type": value, "data": payload, "expire_at": "2024-05-01T12:30:00Z"}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "errors": errs})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBulkCreatePartialFailure(t *testing.T) {
+	var requests, maxInFlight int32
+	server := newBulkServer(t, &requests, &maxInFlight)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	var input []*types.CreateAndUpdateRequest
+	for i := 0; i < 23; i++ {
+		name := fmt.Sprintf("product %d", i)
+		if i == 7 {
+			name = "bad"
+		}
+		input = append(input, &types.CreateAndUpdateRequest{Model: "product", Payload: map[string]interface{}{"name": name}})
+	}
+	input = append(input, &types.CreateAndUpdateRequest{Model: "product"})
+
+	result, err := BulkCreateTyped[Product](client, context.Background(), input, BulkOptions{ChunkSize: 5, Concurrency: 2})
+
+	var bulkErr *BulkError
+	if !errors.As(err, &bulkErr) || bulkErr.Failed != 2 || bulkErr.Total != 24 {
+		t.Fatalf("Expected BulkError with 2 failures, got %v", err)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Error("Expected the validation failure to be reachable through the BulkError")
+	}
+	if result.Succeeded != 22 || result.Failed != 2 {
+		t.Errorf("Expected 22 successes and 2 failures, got %d/%d", result.Succeeded, result.Failed)
+	}
+
+	var gqlErr *GraphQLError
+	if item := result.Items[7]; !errors.As(item.Err, &gqlErr) || gqlErr.Message != "invalid payload" {
+		t.Errorf("Expected item 7 to carry its GraphQL error, got %v", item.Err)
+	}
+	if item := result.Items[3]; item.Err != nil || item.ID != "new-3" || item.Document.Data.Name != "product 3" {
+		t.Errorf("Unexpected item 3: %+v", item)
+	}
+	if item := result.Items[3]; item.Document.ExpireAt != 1714566600 {
+		t.Errorf("Expected the expiry of item 3 in Unix seconds, got %d", item.Document.ExpireAt)
+	}
+	if requests != 5 {
+		t.Errorf("Expected 23 valid items to be sent in 5 chunks, got %d requests", requests)
+	}
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+}
+
+func TestBulkUpdateAndDelete(t *testing.T) {
+	var requests, maxInFlight int32
+	server := newBulkServer(t, &requests, &maxInFlight)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	updates := []*types.CreateAndUpdateRequest{
+		{ID: "a", Model: "product", Payload: map[string]interface{}{"name": "A"}},
+		{ID: "b", Model: "product", Payload: map[string]interface{}{"name": "B"}},
+	}
+	result, err := client.BulkUpdate(ctx, updates, BulkOptions{})
+	if err != nil {
+		t.Fatalf("BulkUpdate failed: %v", err)
+	}
+	if result.Items[1].ID != "b" || result.Items[1].Document.Data["name"] != "B" {
+		t.Errorf("Unexpected update result: %+v", result.Items[1])
+	}
+
+	result, err = client.BulkDelete(ctx, "product", []string{"a", "b", "c"}, BulkOptions{})
+	if err != nil {
+		t.Fatalf("BulkDelete failed: %v", err)
+	}
+	if result.Succeeded != 3 || result.Items[2].ID != "c" {
+		t.Errorf("Unexpected delete result: %+v", result)
+	}
+	if requests != 2 {
+		t.Errorf("Expected one request per bulk call, got %d", requests)
+	}
+}
+
+func TestBulkChunkFailure(t *testing.T) {
+	server := newStaticServer(t, http.StatusInternalServerError, "down")
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	result, err := client.BulkDelete(context.Background(), "product", []string{"a", "b"}, BulkOptions{})
+	if err == nil || result.Failed != 2 {
+		t.Fatalf("Expected every item to fail, got %+v (%v)", result, err)
+	}
+	var httpErr *HTTPError
+	if !errors.As(result.Items[0].Err, &httpErr) {
+		t.Errorf("Expected HTTPError on item, got %v", result.Items[0].Err)
+	}
+}
@@ -4,20 +4,34 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/apito-io/types"
 	"github.com/apito-io/types/interfaces"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client represents the Apito SDK client
 type Client struct {
-	baseURL    string
-	apiKey     string
-	httpClient *http.Client
+	baseURL      string
+	credentials  CredentialProvider
+	telemetry    *telemetry
+	logger       *roundTripLogger
+	httpClient   *http.Client
+	retryPolicy  *RetryPolicy
+	interceptors []Interceptor
+	handler      RoundTripFunc
+
+	serverExtensions bool
 }
 
 // Config represents the SDK configuration
@@ -26,6 +40,38 @@ type Config struct {
 	APIKey     string        // API key for authentication (X-APITO-KEY header)
 	Timeout    time.Duration // HTTP client timeout (default: 30 seconds)
 	HTTPClient *http.Client  // Custom HTTP client (optional)
+
+	// Credentials supplies the API key on every request, allowing keys to be rotated
+	// without recreating the client (optional, APIKey is used when nil)
+	Credentials CredentialProvider
+
+	// RetryPolicy enables automatic retries of failed round-trips (optional, no retries when nil)
+	RetryPolicy *RetryPolicy
+
+	// Interceptors wrap every GraphQL round-trip, the first one being the outermost (optional, see Use)
+	Interceptors []Interceptor
+
+	// TracerProvider and MeterProvider enable OpenTelemetry spans and metrics for every
+	// operation (optional, no telemetry is recorded when nil)
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+
+	// Propagator injects the trace context into outgoing requests when tracing is enabled
+	// (default: W3C trace context)
+	Propagator propagation.TextMapPropagator
+
+	// Logger logs every round-trip with secrets redacted (optional, nothing is logged when nil)
+	Logger     *slog.Logger
+	LogOptions LogOptions
+
+	// ServerExtensions enables the calls relying on server operations outside the documented
+	// Apito schema: status changes (updateModelDataStatus, scheduleModelDataStatus), status
+	// filters (SearchOptions.Status, Drafts, Published) and revision history
+	// (listModelDataRevisions, getModelDataRevision). Without it they fail with ErrUnsupported
+	// before sending a request. Set it only for a server implementing them.
+	// Sort, field selection, locales, soft delete and aggregation predate the flag and stay
+	// ungated for compatibility; a server lacking them rejects the request with ErrValidation.
+	ServerExtensions bool
 }
 
 // NewClient creates a new Apito SDK client
@@ -41,27 +87,110 @@ func NewClient(config Config) *Client {
 		}
 	}
 
-	return &Client{
-		baseURL:    config.BaseURL,
-		apiKey:     config.APIKey,
-		httpClient: httpClient,
+	credentials := config.Credentials
+	if credentials == nil {
+		credentials = StaticCredentials(config.APIKey)
 	}
+
+	client := &Client{
+		baseURL:      config.BaseURL,
+		credentials:  credentials,
+		telemetry:    newTelemetry(config),
+		logger:       newRoundTripLogger(config.Logger, config.LogOptions),
+		httpClient:   httpClient,
+		interceptors: config.Interceptors,
+
+		serverExtensions: config.ServerExtensions,
+	}
+
+	if config.RetryPolicy != nil {
+		client.retryPolicy = config.RetryPolicy.withDefaults()
+	}
+
+	client.handler = chainInterceptors(config.Interceptors, client.send)
+
+	return client
+}
+
+// requireServerExtensions fails with ErrUnsupported unless Config.ServerExtensions is set
+func (c *Client) requireServerExtensions(operation string) error {
+	if !c.serverExtensions {
+		return fmt.Errorf("%s requires Config.ServerExtensions: %w", operation, ErrUnsupported)
+	}
+	return nil
 }
 
 // executeGraphQL executes a GraphQL query or mutation
 func (c *Client) executeGraphQL(ctx context.Context, query string, variables map[string]interface{}) (*types.GraphQLResponse, error) {
-	
-	var tenantID string
-	if ctx.Value("tenant_id") != nil {
-		tenantID = ctx.Value("tenant_id").(string)
+	return c.executeGraphQLInto(ctx, query, variables, nil)
+}
+
+// executeGraphQLInto executes a GraphQL query or mutation, decoding the data fields listed
+// in targets straight into them while the response is read (see decodeGraphQLResponse).
+// decodeResponseField must still be called to pick up each field.
+func (c *Client) executeGraphQLInto(ctx context.Context, query string, variables map[string]interface{}, targets map[string]interface{}) (*types.GraphQLResponse, error) {
+	opType, opName := parseOperation(query)
+	op := &Operation{
+		Name:      opName,
+		Type:      opType,
+		Query:     query,
+		Variables: variables,
+		TenantID:  TenantFromContext(ctx),
+		Header:    requestHeaders(ctx),
+		targets:   targets,
+	}
+
+	ctx, finish := c.telemetry.startOperation(ctx, op)
+	response, err := c.authorizedRoundTrip(ctx, op)
+	finish(err)
+	return response, err
+}
+
+// authorizedRoundTrip attaches the API key and runs the operation through the interceptor chain
+func (c *Client) authorizedRoundTrip(ctx context.Context, op *Operation) (*types.GraphQLResponse, error) {
+	// A key set with WithAPIKey takes precedence over the credential provider
+	if op.Header.Get("X-Apito-Key") != "" {
+		return c.handler(ctx, op)
 	}
 
+	apiKey, err := c.credentials.APIKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	op.Header.Set("X-Apito-Key", apiKey)
+
+	response, err := c.handler(ctx, op)
+	if !errors.Is(err, ErrUnauthorized) {
+		return response, err
+	}
+
+	// The key may have been rotated since it was read: retry once with a refreshed one
+	refreshed, refreshErr := c.refreshAPIKey(ctx)
+	if refreshErr != nil || refreshed == apiKey {
+		return response, err
+	}
+	op.Header.Set("X-Apito-Key", refreshed)
+	return c.handler(ctx, op)
+}
+
+// refreshAPIKey reloads the credential, when the provider supports it, and returns the current key
+func (c *Client) refreshAPIKey(ctx context.Context) (string, error) {
+	if refresher, ok := c.credentials.(CredentialRefresher); ok {
+		if err := refresher.Refresh(ctx); err != nil {
+			return "", err
+		}
+	}
+	return c.credentials.APIKey(ctx)
+}
+
+// send encodes an operation and performs the HTTP round-trip, retrying according to the retry policy
+func (c *Client) send(ctx context.Context, op *Operation) (*types.GraphQLResponse, error) {
 	payload := map[string]interface{}{
-		"query": query,
+		"query": op.Query,
 	}
 
-	if variables != nil {
-		payload["variables"] = variables
+	if op.Variables != nil {
+		payload["variables"] = op.Variables
 	}
 
 	jsonData, err := json.Marshal(payload)
@@ -69,42 +198,114 @@ func (c *Client) executeGraphQL(ctx context.Context, query string, variables map
 		return nil, fmt.Errorf("failed to marshal GraphQL payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewBuffer(jsonData))
+	if c.retryPolicy == nil || !c.retryPolicy.allows(op.Type) {
+		return c.roundTrip(ctx, jsonData, op)
+	}
+
+	attempt := 1
+	for {
+		response, err := c.roundTrip(ctx, jsonData, op)
+		if err == nil {
+			return response, nil
+		}
+		if attempt >= c.retryPolicy.MaxAttempts || !c.retryPolicy.shouldRetry(ctx, err) {
+			return response, &RetryError{Attempts: attempt, Err: err}
+		}
+		if !sleepContext(ctx, c.retryPolicy.delay(attempt, err)) {
+			return response, &RetryError{Attempts: attempt, Err: err}
+		}
+		attempt++
+	}
+}
+
+// roundTrip performs a single HTTP round-trip of an encoded GraphQL payload
+func (c *Client) roundTrip(ctx context.Context, jsonData []byte, op *Operation) (response *types.GraphQLResponse, err error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
+	start := time.Now()
+	statusCode, responseSize := 0, 0
+	defer func() {
+		c.logger.log(ctx, op, req.Header, start, len(jsonData), statusCode, responseSize, err)
+	}()
+
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Apito-Key", c.apiKey)
-	if tenantID != "" {
-		req.Header.Set("X-Apito-Tenant-ID", tenantID)
+	c.telemetry.inject(ctx, req.Header)
+	if op.TenantID != "" {
+		req.Header.Set("X-Apito-Tenant-ID", op.TenantID)
+	}
+	for key, values := range op.Header {
+		req.Header[key] = values
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
+		return nil, &transportError{err: err}
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, string(body))
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, &transportError{err: fmt.Errorf("failed to read response body: %w", err)}
+		}
+		statusCode, responseSize = resp.StatusCode, len(body)
+		c.telemetry.recordRoundTrip(ctx, op, len(jsonData), len(body), resp.StatusCode)
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}
 	}
 
-	var response types.GraphQLResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal GraphQL response: %w", err)
+	// The body is decoded while it is read, then drained so that its size is known
+	// and the connection can be reused
+	body := &countingReader{r: resp.Body}
+	response, decodeErr := decodeGraphQLResponse(body, op.targets)
+	io.Copy(io.Discard, body)
+	if body.err != nil {
+		return nil, &transportError{err: fmt.Errorf("failed to read response body: %w", body.err)}
 	}
+	statusCode, responseSize = resp.StatusCode, body.n
+	c.telemetry.recordRoundTrip(ctx, op, len(jsonData), body.n, resp.StatusCode)
 
-	if len(response.Errors) > 0 {
-		return &response, fmt.Errorf("GraphQL errors: %v", response.Errors)
+	if response != nil && len(response.Errors) > 0 {
+		return response, newGraphQLErrors(response.Errors)
 	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	return response, nil
+}
+
+// transportError is returned when the HTTP request could not be completed
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return fmt.Sprintf("failed to execute HTTP request: %v", e.err)
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
 
-	return &response, nil
+// parseOperation returns the operation type ("query", "mutation" or "subscription")
+// and the operation name of a GraphQL document. Anonymous shorthand queries yield ("query", "").
+func parseOperation(query string) (opType string, opName string) {
+	q := strings.TrimSpace(query)
+	for _, t := range []string{"query", "mutation", "subscription"} {
+		if !strings.HasPrefix(q, t) {
+			continue
+		}
+		rest := strings.TrimSpace(q[len(t):])
+		end := strings.IndexAny(rest, "({ \t\r\n@")
+		if end < 0 {
+			end = len(rest)
+		}
+		return t, rest[:end]
+	}
+	return "query", ""
 }
 
 // GenerateTenantToken generates a new tenant token for the specified tenant ID
@@ -129,17 +330,17 @@ func (c *Client) GenerateTenantToken(ctx context.Context, token string, tenantID
 
 	data, ok := response.Data.(map[string]interface{})
 	if !ok {
-		return "", fmt.Errorf("unexpected response format")
+		return "", &DecodeError{Err: errUnexpectedFormat}
 	}
 
 	result, ok := data["generateTenantToken"].(map[string]interface{})
 	if !ok {
-		return "", fmt.Errorf("unexpected response format")
+		return "", &DecodeError{Field: "generateTenantToken", Err: errUnexpectedFormat}
 	}
 
 	tokenStr, ok := result["token"].(string)
 	if !ok {
-		return "", fmt.Errorf("unexpected token format")
+		return "", &DecodeError{Field: "generateTenantToken.token", Err: errUnexpectedFormat}
 	}
 
 	return tokenStr, nil
@@ -149,65 +350,132 @@ func (c *Client) GenerateTenantToken(ctx context.Context, token string, tenantID
 // TYPED GENERIC FUNCTIONS
 // =============================================================================
 
+// The typed functions decode document data straight into T while the response is read.
+// Projected reads (WithFields) go through the generic documents instead, so that the
+// projection is applied before the data reaches T.
+
 // GetSingleResourceTyped retrieves a single resource by model and ID with typed data
 func GetSingleResourceTyped[T any](c *Client, ctx context.Context, model, _id string, singlePageData bool) (*types.TypedDocumentStructure[T], error) {
-	rawDocument, err := c.GetSingleResource(ctx, model, _id, singlePageData)
-	if err != nil {
+	if isProjected(ctx, nil) {
+		rawDocument, err := c.GetSingleResource(ctx, model, _id, singlePageData)
+		if err != nil {
+			return nil, err
+		}
+		return convertToTypedDocument[T](rawDocument)
+	}
+
+	var document *typedDocument[T]
+	if err := c.getSingleData(ctx, model, _id, singlePageData, &document); err != nil {
 		return nil, err
 	}
-	return convertToTypedDocument[T](rawDocument)
+	return document.typed(), nil
 }
 
-// SearchResourcesTyped searches for resources with typed results
+// SearchResourcesTyped searches for resources with typed results. aggregate is ignored, as for SearchResources.
 func SearchResourcesTyped[T any](c *Client, ctx context.Context, model string, filter map[string]interface{}, aggregate bool) (*types.TypedSearchResult[T], error) {
-	rawResults, err := c.SearchResources(ctx, model, filter, aggregate)
+	opts, err := searchOptionsFromMap(filter)
 	if err != nil {
 		return nil, err
 	}
-	return convertToTypedSearchResult[T](rawResults)
+
+	return SearchTyped[T](c, ctx, model, opts)
 }
 
 // GetRelationDocumentsTyped retrieves related documents with typed results
 func GetRelationDocumentsTyped[T any](c *Client, ctx context.Context, _id string, connection map[string]interface{}) (*types.TypedSearchResult[T], error) {
-	rawResults, err := c.GetRelationDocuments(ctx, _id, connection)
-	if err != nil {
+	if isProjected(ctx, nil) {
+		rawResults, err := c.GetRelationDocuments(ctx, _id, connection)
+		if err != nil {
+			return nil, err
+		}
+		return convertToTypedSearchResult[T](rawResults)
+	}
+
+	var searchResult *typedSearchResult[T]
+	if err := c.getRelationDocuments(ctx, _id, connection, &searchResult); err != nil {
 		return nil, err
 	}
-	return convertToTypedSearchResult[T](rawResults)
+	return searchResult.typed(), nil
 }
 
 // CreateNewResourceTyped creates a new resource with typed result
 func CreateNewResourceTyped[T any](c *Client, ctx context.Context, request *types.CreateAndUpdateRequest) (*types.TypedDocumentStructure[T], error) {
-	rawDocument, err := c.CreateNewResource(ctx, request)
-	if err != nil {
+	var document *typedDocument[T]
+	if err := c.createNewResource(ctx, request, CreateOptions{}, &document); err != nil {
 		return nil, err
 	}
-	return convertToTypedDocument[T](rawDocument)
+	return document.typed(), nil
 }
 
 // UpdateResourceTyped updates a resource with typed result
 func UpdateResourceTyped[T any](c *Client, ctx context.Context, request *types.CreateAndUpdateRequest) (*types.TypedDocumentStructure[T], error) {
-	rawDocument, err := c.UpdateResource(ctx, request)
-	if err != nil {
+	var document *typedDocument[T]
+	if err := c.updateResource(ctx, request, UpdateOptions{}, &document); err != nil {
 		return nil, err
 	}
-	return convertToTypedDocument[T](rawDocument)
+	return document.typed(), nil
 }
 
 // =============================================================================
 // HELPER FUNCTIONS FOR TYPE CONVERSION
 // =============================================================================
 
+// decodeResponseField decodes the named top-level field of a GraphQL response into target.
+// A null field is reported as ErrNotFound. Fields already decoded into the target type
+// while the response was read are assigned as they are; others, such as responses
+// built by interceptors, are converted through JSON.
+func decodeResponseField(response *types.GraphQLResponse, field string, target interface{}) error {
+	data, ok := response.Data.(map[string]interface{})
+	if !ok {
+		return &DecodeError{Err: errUnexpectedFormat}
+	}
+
+	raw, ok := data[field]
+	if !ok {
+		return &DecodeError{Field: field, Err: fmt.Errorf("%s not found in response", field)}
+	}
+	if raw == nil {
+		return fmt.Errorf("%s returned null: %w", field, ErrNotFound)
+	}
+
+	if value := reflect.ValueOf(target).Elem(); reflect.TypeOf(raw) == value.Type() {
+		value.Set(reflect.ValueOf(raw))
+		return nil
+	}
+
+	// Convert interface{} to the target structure
+	rawJSON, err := json.Marshal(raw)
+	if err != nil {
+		return &DecodeError{Field: field, Err: err}
+	}
+
+	if err := decodeTarget(json.NewDecoder(bytes.NewReader(rawJSON)), target); err != nil {
+		return &DecodeError{Field: field, Err: err}
+	}
+
+	return nil
+}
+
+// validateWhere runs client-side validation on where filters that support it, such as where.Expr
+func validateWhere(where interface{}) error {
+	if v, ok := where.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return &validationError{message: err.Error(), err: err}
+		}
+	}
+	return nil
+}
+
 // convertToTypedDocument converts a raw DefaultDocumentStructure to a typed document
 func convertToTypedDocument[T any](rawDoc *types.DefaultDocumentStructure) (*types.TypedDocumentStructure[T], error) {
 	dataJSON, err := json.Marshal(rawDoc.Data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal raw data: %w", err)
+		return nil, &DecodeError{Field: "data", Err: err}
 	}
 
 	var typedData T
 	if err := json.Unmarshal(dataJSON, &typedData); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal to typed data: %w", err)
+		return nil, &DecodeError{Field: "data", Err: err}
 	}
 
 	return &types.TypedDocumentStructure[T]{
@@ -239,12 +507,13 @@ func convertToTypedSearchResult[T any](rawResults *types.SearchResult) (*types.T
 	return typedResults, nil
 }
 
-// parseExpireAt converts string expire_at to int64
+// parseExpireAt converts string expire_at to Unix seconds, 0 when the document does not expire
 func parseExpireAt(expireAt string) int64 {
-	if expireAt == "" {
+	t, err := ParseExpireAt(expireAt)
+	if err != nil || t.IsZero() {
 		return 0
 	}
-	return 0 // Could implement actual parsing logic here
+	return t.Unix()
 }
 
 // =============================================================================
@@ -306,9 +575,23 @@ func (c *Client) GetProjectDetails(ctx context.Context, projectID string) (*prot
 
 // GetSingleResource retrieves a single resource by model and ID, with optional single page data
 func (c *Client) GetSingleResource(ctx context.Context, model, _id string, singlePageData bool) (*types.DefaultDocumentStructure, error) {
-	query := `
-		query GetSingleData($model: String, $_id: String!, $single_page_data: Boolean) {
-			getSingleData(model: $model, _id: $_id, single_page_data: $single_page_data) {
+	var document *types.DefaultDocumentStructure
+	if err := c.getSingleData(ctx, model, _id, singlePageData, &document); err != nil {
+		return nil, err
+	}
+	projectDocument(document, fieldsFromContext(ctx))
+
+	return document, nil
+}
+
+// getSingleData runs the getSingleData query, decoding the document into target
+func (c *Client) getSingleData(ctx context.Context, model, _id string, singlePageData bool, target interface{}) error {
+	fields := fieldsFromContext(ctx)
+	fieldsDeclaration, fieldsArg := fieldsArgument(fields)
+
+	query := fmt.Sprintf(`
+		query GetSingleData($model: String, $_id: String!, $single_page_data: Boolean%s) {
+			getSingleData(model: $model, _id: $_id, single_page_data: $single_page_data%s) {
 				_key
 				data
 				meta {
@@ -324,122 +607,57 @@ func (c *Client) GetSingleResource(ctx context.Context, model, _id string, singl
 				type
 			}
 		}
-	`
+	`, fieldsDeclaration, fieldsArg)
 	variables := map[string]interface{}{
 		"model":            model,
 		"_id":              _id,
 		"single_page_data": singlePageData,
 	}
-
-	response, err := c.executeGraphQL(ctx, query, variables)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get single resource: %w", err)
-	}
-
-	data, ok := response.Data.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response format")
-	}
-
-	singleDataRaw, ok := data["getSingleData"]
-	if !ok {
-		return nil, fmt.Errorf("getSingleData not found in response")
+	if len(fields) > 0 {
+		variables["fields"] = fields
 	}
 
-	// Convert interface{} to *shared.DefaultDocumentStructure
-	singleDataJSON, err := json.Marshal(singleDataRaw)
+	response, err := c.executeGraphQLInto(ctx, query, variables, map[string]interface{}{"getSingleData": target})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal getSingleData: %w", err)
-	}
-
-	var document types.DefaultDocumentStructure
-	if err := json.Unmarshal(singleDataJSON, &document); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal getSingleData: %w", err)
+		return fmt.Errorf("failed to get single resource: %w", err)
 	}
 
-	return &document, nil
+	return decodeResponseField(response, "getSingleData", target)
 }
 
-// SearchResources searches for resources in the specified model using the provided filter
+// SearchResources searches for resources in the specified model using the provided filter.
+// aggregate is kept for compatibility and has no effect; use Count and Aggregate for
+// counts and group-by buckets.
 func (c *Client) SearchResources(ctx context.Context, model string, filter map[string]interface{}, aggregate bool) (*types.SearchResult, error) {
-	query := `
-		query GetModelData($model: String!, $page: Int, $limit: Int, $_key: JSON, $where: JSON, $search: String) {
-			getModelData(model: $model, page: $page, limit: $limit, _key: $_key, where: $where, search: $search) {
-				results {
-					id
-					relation_doc_id
-					data
-					type
-					expire_at
-					meta {
-						created_at
-						updated_at
-						status
-						root_revision_id
-					}
-				}
-				count
-			}
-		}
-	`
-
-	variables := map[string]interface{}{
-		"model": model,
-	}
-
-	// Add filter parameters if provided
-	if filter != nil {
-		if _key, ok := filter["_key"]; ok {
-			variables["_key"] = _key
-		}
-		if page, ok := filter["page"]; ok {
-			variables["page"] = page
-		}
-		if limit, ok := filter["limit"]; ok {
-			variables["limit"] = limit
-		}
-		if where, ok := filter["where"]; ok {
-			variables["where"] = where
-		}
-		if search, ok := filter["search"]; ok {
-			variables["search"] = search
-		}
-	}
-
-	response, err := c.executeGraphQL(ctx, query, variables)
+	opts, err := searchOptionsFromMap(filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search resources: %w", err)
-	}
-
-	data, ok := response.Data.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response format")
+		return nil, err
 	}
 
-	modelDataRaw, ok := data["getModelData"]
-	if !ok {
-		return nil, fmt.Errorf("getModelData not found in response")
-	}
+	return c.Search(ctx, model, opts)
+}
 
-	// Convert interface{} to SearchResult
-	modelDataJSON, err := json.Marshal(modelDataRaw)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal getModelData: %w", err)
+// GetRelationDocuments retrieves related documents for the given ID and connection parameters
+func (c *Client) GetRelationDocuments(ctx context.Context, _id string, connection map[string]interface{}) (*types.SearchResult, error) {
+	var searchResult *types.SearchResult
+	if err := c.getRelationDocuments(ctx, _id, connection, &searchResult); err != nil {
+		return nil, err
 	}
-
-	var searchResult types.SearchResult
-	if err := json.Unmarshal(modelDataJSON, &searchResult); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal getModelData: %w", err)
+	for _, doc := range searchResult.Results {
+		projectDocument(doc, fieldsFromContext(ctx))
 	}
 
-	return &searchResult, nil
+	return searchResult, nil
 }
 
-// GetRelationDocuments retrieves related documents for the given ID and connection parameters
-func (c *Client) GetRelationDocuments(ctx context.Context, _id string, connection map[string]interface{}) (*types.SearchResult, error) {
-	query := `
-		query GetModelData($model: String!, $page: Int, $limit: Int, $where: JSON, $search: String, $connection : ListAllDataDetailedOfAModelConnectionPayload) {
-			getModelData(model: $model, page: $page, limit: $limit, where: $where, search: $search, connection: $connection) {
+// getRelationDocuments runs the getModelData query of a connection, decoding the result into target
+func (c *Client) getRelationDocuments(ctx context.Context, _id string, connection map[string]interface{}, target interface{}) error {
+	fields := fieldsFromContext(ctx)
+	fieldsDeclaration, fieldsArg := fieldsArgument(fields)
+
+	query := fmt.Sprintf(`
+		query GetModelData($model: String!, $page: Int, $limit: Int, $where: JSON, $search: String, $connection : ListAllDataDetailedOfAModelConnectionPayload%s) {
+			getModelData(model: $model, page: $page, limit: $limit, where: $where, search: $search, connection: $connection%s) {
 				results {
 					id
 					relation_doc_id
@@ -456,17 +674,20 @@ func (c *Client) GetRelationDocuments(ctx context.Context, _id string, connectio
 				count
 			}
 		}
-	`
+	`, fieldsDeclaration, fieldsArg)
 
 	variables := map[string]interface{}{
 		"connection": connection,
 	}
+	if len(fields) > 0 {
+		variables["fields"] = fields
+	}
 
 	// Extract model from connection if available
 	if model, ok := connection["model"].(string); ok {
 		variables["model"] = model
 	} else {
-		return nil, fmt.Errorf("model is required in connection parameters")
+		return newValidationError("model is required in connection parameters")
 	}
 
 	// Add filter parameters if provided in connection
@@ -478,6 +699,9 @@ func (c *Client) GetRelationDocuments(ctx context.Context, _id string, connectio
 			variables["limit"] = limit
 		}
 		if where, ok := filter["where"]; ok {
+			if err := validateWhere(where); err != nil {
+				return err
+			}
 			variables["where"] = where
 		}
 		if search, ok := filter["search"]; ok {
@@ -485,57 +709,54 @@ func (c *Client) GetRelationDocuments(ctx context.Context, _id string, connectio
 		}
 	}
 
-	response, err := c.executeGraphQL(ctx, query, variables)
+	response, err := c.executeGraphQLInto(ctx, query, variables, map[string]interface{}{"getModelData": target})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get relation documents: %w", err)
-	}
-
-	data, ok := response.Data.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response format")
-	}
-
-	modelDataRaw, ok := data["getModelData"]
-	if !ok {
-		return nil, fmt.Errorf("getModelData not found in response")
+		return fmt.Errorf("failed to get relation documents: %w", err)
 	}
 
-	// Convert interface{} to SearchResult
-	modelDataJSON, err := json.Marshal(modelDataRaw)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal getModelData: %w", err)
-	}
+	return decodeResponseField(response, "getModelData", target)
+}
 
-	var searchResult types.SearchResult
-	if err := json.Unmarshal(modelDataJSON, &searchResult); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal getModelData: %w", err)
+// CreateNewResource creates a new resource in the specified model with the given data and connections
+func (c *Client) CreateNewResource(ctx context.Context, request *types.CreateAndUpdateRequest) (*types.DefaultDocumentStructure, error) {
+	var document *types.DefaultDocumentStructure
+	if err := c.createNewResource(ctx, request, CreateOptions{}, &document); err != nil {
+		return nil, err
 	}
 
-	return &searchResult, nil
+	return document, nil
 }
 
-// CreateNewResource creates a new resource in the specified model with the given data and connections
-func (c *Client) CreateNewResource(ctx context.Context, request *types.CreateAndUpdateRequest) (*types.DefaultDocumentStructure, error) {
-	
+// createNewResource runs the create mutation, decoding the created document into target
+func (c *Client) createNewResource(ctx context.Context, request *types.CreateAndUpdateRequest, opts CreateOptions, target interface{}) error {
 	if request.Model == "" {
-		return nil, fmt.Errorf("model is required")
+		return newValidationError("model is required")
 	}
 
 	if request.Payload == nil {
-		return nil, fmt.Errorf("payload is required")
+		return newValidationError("payload is required")
 	}
-	
-	query := `
-		mutation CreateNewData($model: String!, $single_page_data: Boolean, $payload: JSON!, $connect: JSON) {
+
+	variables := map[string]interface{}{
+		"model":            request.Model,
+		"payload":          request.Payload,
+		"single_page_data": request.SinglePageData,
+	}
+	expireAt, hasExpiry := opts.expiry()
+	expiryDeclaration, expiryArg := expiryArgument(variables, expireAt, hasExpiry)
+
+	query := fmt.Sprintf(`
+		mutation CreateNewData($model: String!, $single_page_data: Boolean, $payload: JSON!, $connect: JSON%s) {
 			upsertModelData(
 				connect: $connect
 				model_name: $model
 				single_page_data: $single_page_data
-				payload: $payload
+				payload: $payload%s
 			) {
 				id
 				type
 				data
+				expire_at
 				meta {
 					created_at
 					updated_at
@@ -545,65 +766,63 @@ func (c *Client) CreateNewResource(ctx context.Context, request *types.CreateAnd
 				}
 			}
 		}
-	`
-
-	variables := map[string]interface{}{
-		"model": request.Model,
-		"payload":  request.Payload,
-		"single_page_data": request.SinglePageData,
-	}
+	`, expiryDeclaration, expiryArg)
 
 	if request.Connect != nil {
 		variables["connect"] = request.Connect
 	}
 
-	response, err := c.executeGraphQL(ctx, query, variables)
+	response, err := c.executeGraphQLInto(ctx, query, variables, map[string]interface{}{"upsertModelData": target})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create new resource: %w", err)
+		return fmt.Errorf("failed to create new resource: %w", err)
 	}
 
-	responseData, ok := response.Data.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response format")
-	}
-
-	singleDataRaw, ok := responseData["upsertModelData"]
-	if !ok {
-		return nil, fmt.Errorf("upsertModelData not found in response")
-	}
-
-	// Convert interface{} to *shared.DefaultDocumentStructure
-	singleDataJSON, err := json.Marshal(singleDataRaw)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal getSingleData: %w", err)
-	}
+	return decodeResponseField(response, "upsertModelData", target)
+}
 
-	var document types.DefaultDocumentStructure
-	if err := json.Unmarshal(singleDataJSON, &document); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal getSingleData: %w", err)
+// UpdateResource updates an existing resource by model and ID, with optional single page data, data updates, and connection changes
+func (c *Client) UpdateResource(ctx context.Context, request *types.CreateAndUpdateRequest) (*types.DefaultDocumentStructure, error) {
+	var document *types.DefaultDocumentStructure
+	if err := c.updateResource(ctx, request, UpdateOptions{}, &document); err != nil {
+		return nil, err
 	}
 
-	return &document, nil
+	return document, nil
 }
 
-// UpdateResource updates an existing resource by model and ID, with optional single page data, data updates, and connection changes
-func (c *Client) UpdateResource(ctx context.Context, request *types.CreateAndUpdateRequest) (*types.DefaultDocumentStructure, error) {
+// updateResource runs the update mutation, decoding the updated document into target
+func (c *Client) updateResource(ctx context.Context, request *types.CreateAndUpdateRequest, opts UpdateOptions, target interface{}) error {
 	// fetch tenant_id from data if available
 
 	if request.ID == "" {
-		return nil, fmt.Errorf("id is required")
+		return newValidationError("id is required")
 	}
 
 	if request.Model == "" {
-		return nil, fmt.Errorf("model is required")
+		return newValidationError("model is required")
 	}
 
 	if request.Payload == nil {
-		return nil, fmt.Errorf("payload is required")
+		return newValidationError("payload is required")
 	}
 
-	query := `
-		mutation UpdateModelData($_id: String!, $model: String!, $single_page_data: Boolean, $force_update: Boolean, $payload: JSON!, $connect: JSON, $disconnect: JSON) {
+	preconditionDeclaration, preconditionArg, err := c.updatePrecondition(ctx, request, opts)
+	if err != nil {
+		return err
+	}
+
+	variables := map[string]interface{}{
+		"_id":              request.ID,
+		"model":            request.Model,
+		"payload":          request.Payload,
+		"single_page_data": request.SinglePageData,
+		"force_update":     request.ForceUpdate,
+	}
+	expireAt, hasExpiry := opts.expiry()
+	expiryDeclaration, expiryArg := expiryArgument(variables, expireAt, hasExpiry)
+
+	query := fmt.Sprintf(`
+		mutation UpdateModelData($_id: String!, $model: String!, $single_page_data: Boolean, $force_update: Boolean, $payload: JSON!, $connect: JSON, $disconnect: JSON%s%s) {
 			upsertModelData(
 				connect: $connect
 				model_name: $model
@@ -611,11 +830,12 @@ func (c *Client) UpdateResource(ctx context.Context, request *types.CreateAndUpd
 				force_update: $force_update
 				disconnect: $disconnect
 				_id: $_id
-				payload: $payload
+				payload: $payload%s%s
 			) {
 				id
 				type
 				data
+				expire_at
 				meta {
 					created_at
 					updated_at
@@ -625,15 +845,7 @@ func (c *Client) UpdateResource(ctx context.Context, request *types.CreateAndUpd
 				}
 			}
 		}
-	`
-
-	variables := map[string]interface{}{
-		"_id":   request.ID,
-		"model": request.Model,
-		"payload":  request.Payload,
-		"single_page_data": request.SinglePageData,
-		"force_update": request.ForceUpdate,
-	}
+	`, preconditionDeclaration, expiryDeclaration, preconditionArg, expiryArg)
 
 	if request.Connect != nil {
 		variables["connect"] = request.Connect
@@ -641,62 +853,27 @@ func (c *Client) UpdateResource(ctx context.Context, request *types.CreateAndUpd
 	if request.Disconnect != nil {
 		variables["disconnect"] = request.Disconnect
 	}
-
-	response, err := c.executeGraphQL(ctx, query, variables)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update resource: %w", err)
-	}
-
-	responseData, ok := response.Data.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response format")
+	if preconditionArg != "" {
+		variables["expected_revision"] = opts.ExpectedRevision
 	}
 
-	singleDataRaw, ok := responseData["upsertModelData"]
-	if !ok {
-		return nil, fmt.Errorf("upsertModelData not found in response")
+	response, err := c.executeGraphQLInto(ctx, query, variables, map[string]interface{}{"upsertModelData": target})
+	if errors.Is(err, ErrConflict) {
+		return &ConflictError{Model: request.Model, ID: request.ID, ExpectedRevision: opts.ExpectedRevision, Err: err}
 	}
-
-	// Convert interface{} to *shared.DefaultDocumentStructure
-	singleDataJSON, err := json.Marshal(singleDataRaw)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal getSingleData: %w", err)
-	}
-
-	var document types.DefaultDocumentStructure
-	if err := json.Unmarshal(singleDataJSON, &document); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal getSingleData: %w", err)
+		return fmt.Errorf("failed to update resource: %w", err)
 	}
 
-	return &document, nil
+	return decodeResponseField(response, "upsertModelData", target)
 }
 
 // DeleteResource deletes a resource by model and ID
 func (c *Client) DeleteResource(ctx context.Context, model, _id string) error {
-	// Note: This is a placeholder implementation as the exact mutation wasn't found in the schema
-	// You would need to implement the actual deleteData mutation based on your GraphQL schema
-	query := `
-		mutation DeleteData($model: String!, $_id: String!) {
-			deleteModelData(model_name: $model, _id: $_id) {
-				id
-			}
-		}
-	`
-
-	variables := map[string]interface{}{
-		"model": model,
-		"_id":   _id,
-	}
-
-	_, err := c.executeGraphQL(ctx, query, variables)
-	if err != nil {
-		return fmt.Errorf("failed to delete resource: %w", err)
-	}
-
-	return nil
+	_, err := c.Delete(ctx, model, _id, DeleteOptions{})
+	return err
 }
 
-
 // Debug is used to debug the plugin, you can pass data here to debug the plugin
 func (c *Client) Debug(ctx context.Context, stage string, data ...interface{}) (interface{}, error) {
 	// Note: This is a placeholder implementation as the exact mutation wasn't found in the schema
@@ -722,7 +899,7 @@ func (c *Client) Debug(ctx context.Context, stage string, data ...interface{}) (
 
 	responseData, ok := response.Data.(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("unexpected response format")
+		return nil, &DecodeError{Err: errUnexpectedFormat}
 	}
 
 	return responseData["debug"], nil
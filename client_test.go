@@ -75,8 +75,8 @@ func TestNewClient(t *testing.T) {
 		t.Errorf("Expected baseURL %s, got %s", config.BaseURL, client.baseURL)
 	}
 
-	if client.apiKey != config.APIKey {
-		t.Errorf("Expected apiKey %s, got %s", config.APIKey, client.apiKey)
+	if apiKey, _ := client.credentials.APIKey(context.Background()); apiKey != config.APIKey {
+		t.Errorf("Expected apiKey %s, got %s", config.APIKey, apiKey)
 	}
 }
 
@@ -0,0 +1,232 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apito-io/types"
+)
+
+// conflictRetryAttempts is the number of read-modify-write cycles tried by RetryOnConflict
+const conflictRetryAttempts = 5
+
+// conflictBackoff spaces the attempts of RetryOnConflict so that competing writers drift apart
+var conflictBackoff = (&RetryPolicy{BaseBackoff: 20 * time.Millisecond, MaxBackoff: time.Second, Jitter: 0.5}).withDefaults()
+
+// DocumentRevision returns the revision of a document, used as the precondition of optimistic updates.
+// types.MetaField.Revision only flags whether revisions are kept, so the revision is the
+// timestamp of the last change, meta.updated_at. It is empty when the meta was not returned.
+func DocumentRevision(meta *types.MetaField) string {
+	if meta == nil {
+		return ""
+	}
+	return meta.UpdatedAt
+}
+
+// UpdateOptions configures an update
+type UpdateOptions struct {
+	// ExpectedRevision rejects the update with a *ConflictError unless the document is
+	// still at this revision, as returned by DocumentRevision (optional).
+	// Without ServerPrecondition the check is best-effort: the client reads the document
+	// before writing, and a write landing between the read and the update is not detected.
+	ExpectedRevision string
+
+	// ServerPrecondition sends ExpectedRevision to the server as the expected_revision argument,
+	// making the check atomic, instead of reading the document first. The server must support
+	// the argument; it is not part of the documented Apito schema.
+	ServerPrecondition bool
+
+	// TTL makes the document expire this long after the update, by the client clock (optional)
+	TTL time.Duration
+
+	// ExpireAt makes the document expire at the given time; it takes precedence over TTL (optional)
+	ExpireAt time.Time
+
+	// ClearExpiry removes the expiry of the document, see ClearTTL
+	ClearExpiry bool
+
+	// revisionRead skips the client-side read of ExpectedRevision, which the caller took
+	// from the document it just read
+	revisionRead bool
+}
+
+// ConflictError is returned when an update is rejected because the document changed since it was read.
+// errors.Is(err, ErrConflict) matches it.
+type ConflictError struct {
+	Model            string
+	ID               string
+	ExpectedRevision string
+	ActualRevision   string // Current revision, empty when the server detected the conflict
+	Err              error  // Error reported by the server, if any
+}
+
+func (e *ConflictError) Error() string {
+	if e.ActualRevision != "" {
+		return fmt.Sprintf("document %s of model %s was modified: expected revision %s, found %s", e.ID, e.Model, e.ExpectedRevision, e.ActualRevision)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("document %s of model %s was modified: %v", e.ID, e.Model, e.Err)
+	}
+	return fmt.Sprintf("document %s of model %s was modified since revision %s", e.ID, e.Model, e.ExpectedRevision)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Update updates an existing resource like UpdateResource, with preconditions
+func (c *Client) Update(ctx context.Context, request *types.CreateAndUpdateRequest, opts UpdateOptions) (*types.DefaultDocumentStructure, error) {
+	var document *types.DefaultDocumentStructure
+	if err := c.updateResource(ctx, request, opts, &document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// UpdateTyped updates an existing resource with preconditions and returns a typed document
+func UpdateTyped[T any](c *Client, ctx context.Context, request *types.CreateAndUpdateRequest, opts UpdateOptions) (*types.TypedDocumentStructure[T], error) {
+	var document *typedDocument[T]
+	if err := c.updateResource(ctx, request, opts, &document); err != nil {
+		return nil, err
+	}
+	return document.typed(), nil
+}
+
+// updatePrecondition checks the expected revision client-side, or returns the variable
+// declaration and argument carrying it to the server
+func (c *Client) updatePrecondition(ctx context.Context, request *types.CreateAndUpdateRequest, opts UpdateOptions) (declaration string, argument string, err error) {
+	if opts.ExpectedRevision == "" {
+		return "", "", nil
+	}
+	if opts.ServerPrecondition {
+		return ", $expected_revision: String", "\n\t\t\t\texpected_revision: $expected_revision", nil
+	}
+	if opts.revisionRead {
+		return "", "", nil
+	}
+
+	current, err := c.GetSingleResource(ctx, request.Model, request.ID, request.SinglePageData)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read current revision: %w", err)
+	}
+	if actual := DocumentRevision(current.Meta); actual != opts.ExpectedRevision {
+		return "", "", &ConflictError{Model: request.Model, ID: request.ID, ExpectedRevision: opts.ExpectedRevision, ActualRevision: actual}
+	}
+	return "", "", nil
+}
+
+// RetryOnConflictOptions configures RetryOnConflict
+type RetryOnConflictOptions struct {
+	// ServerPrecondition sends the read revision as the expected_revision argument of the
+	// write, see UpdateOptions. Without it the document is read again right before the write
+	// and the revisions compared, which is best-effort: a write landing between that check
+	// and the write is not detected and gets overwritten.
+	ServerPrecondition bool
+}
+
+// RetryOnConflict reads a document, lets mutate change its data and writes it back with the
+// read revision as precondition. When another writer got there first, the document is read
+// again and mutate reapplied, up to 5 times; the last conflict is then returned in a *RetryError.
+//
+// mutate may run several times and must derive its changes from the document it is given.
+// An error returned by mutate aborts without writing. The whole data is written back:
+// set a key to nil to clear it, deleting it from Data has no effect. A changed ExpireAt is
+// written too, an empty one clearing the expiry.
+func (c *Client) RetryOnConflict(ctx context.Context, model, _id string, mutate func(doc *types.DefaultDocumentStructure) error, opts RetryOnConflictOptions) (*types.DefaultDocumentStructure, error) {
+	var document *types.DefaultDocumentStructure
+	rmw := readModifyWrite{model: model, id: _id, serverPrecondition: opts.ServerPrecondition}
+	if err := c.readModifyWrite(ctx, rmw, mutate, &document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// readModifyWrite describes a read-modify-write cycle
+type readModifyWrite struct {
+	model string
+	id    string
+
+	// expectedRevision fails the cycle with a *ConflictError, without retrying, unless the
+	// document is at this revision when read
+	expectedRevision   string
+	serverPrecondition bool
+
+	// replace writes the data as a whole: keys dropped from it by mutate are sent as null,
+	// clearing them, instead of being left untouched by the merge
+	replace bool
+}
+
+// readModifyWrite reads a document, applies mutate and writes it back with the read revision
+// as precondition, decoding the written document into target. Conflicts start the cycle over
+// with backoff, up to conflictRetryAttempts times, unless an expected revision was given.
+// Without serverPrecondition the precondition is checked client-side by updateResource,
+// reading the document again just before the write.
+func (c *Client) readModifyWrite(ctx context.Context, rmw readModifyWrite, mutate func(doc *types.DefaultDocumentStructure) error, target interface{}) error {
+	// The whole data is read and written, whatever the fields selected on ctx
+	readCtx := WithFields(ctx)
+
+	for attempt := 1; ; attempt++ {
+		doc, err := c.GetSingleResource(readCtx, rmw.model, rmw.id, false)
+		if err != nil {
+			return err
+		}
+		if doc.Data == nil {
+			doc.Data = make(map[string]interface{})
+		}
+		revision := DocumentRevision(doc.Meta)
+		if revision == "" {
+			return newValidationError("document %s of model %s has no revision to update against", rmw.id, rmw.model)
+		}
+		if rmw.expectedRevision != "" && revision != rmw.expectedRevision {
+			return &ConflictError{Model: rmw.model, ID: rmw.id, ExpectedRevision: rmw.expectedRevision, ActualRevision: revision}
+		}
+		expireAt := doc.ExpireAt
+		keys := make([]string, 0, len(doc.Data))
+		for key := range doc.Data {
+			keys = append(keys, key)
+		}
+		if err := mutate(doc); err != nil {
+			return err
+		}
+
+		payload := doc.Data
+		if rmw.replace {
+			payload = make(map[string]interface{}, len(doc.Data))
+			for _, key := range keys {
+				payload[key] = nil
+			}
+			for key, value := range doc.Data {
+				payload[key] = value
+			}
+		}
+
+		opts := UpdateOptions{ExpectedRevision: revision, ServerPrecondition: rmw.serverPrecondition}
+		if doc.ExpireAt != expireAt {
+			if opts.ExpireAt, err = ParseExpireAt(doc.ExpireAt); err != nil {
+				return newValidationError("%v", err)
+			}
+			opts.ClearExpiry = opts.ExpireAt.IsZero()
+		}
+
+		err = c.updateResource(ctx, &types.CreateAndUpdateRequest{
+			ID:      rmw.id,
+			Model:   rmw.model,
+			Payload: payload,
+		}, opts, target)
+		if !errors.Is(err, ErrConflict) || rmw.expectedRevision != "" {
+			return err
+		}
+
+		if attempt >= conflictRetryAttempts || !sleepContext(ctx, conflictBackoff.delay(attempt, err)) {
+			return &RetryError{Attempts: attempt, Err: err}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/apito-io/go-internal-sdk/apitotest"
+	"github.com/apito-io/types"
+)
+
+func TestUpdateExpectedRevision(t *testing.T) {
+	server := apitotest.NewServer()
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	seeded := server.Seed("", "task", map[string]interface{}{"title": "Draft"})
+	doc, err := client.GetSingleResource(ctx, "task", seeded.ID, false)
+	if err != nil {
+		t.Fatalf("GetSingleResource failed: %v", err)
+	}
+	stale := DocumentRevision(doc.Meta)
+	if stale == "" {
+		t.Fatal("Expected the document to have a revision")
+	}
+
+	updated, err := client.Update(ctx, &types.CreateAndUpdateRequest{ID: doc.ID, Model: "task", Payload: map[string]interface{}{"title": "First"}}, UpdateOptions{ExpectedRevision: stale})
+	if err != nil {
+		t.Fatalf("Update with current revision failed: %v", err)
+	}
+	if DocumentRevision(updated.Meta) == stale {
+		t.Fatal("Expected the update to change the revision")
+	}
+
+	for _, serverSide := range []bool{false, true} {
+		_, err := client.Update(ctx, &types.CreateAndUpdateRequest{ID: doc.ID, Model: "task", Payload: map[string]interface{}{"title": "Second"}},
+			UpdateOptions{ExpectedRevision: stale, ServerPrecondition: serverSide})
+
+		var conflict *ConflictError
+		if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) {
+			t.Fatalf("server precondition %v: expected a ConflictError, got %v", serverSide, err)
+		}
+		if conflict.ID != doc.ID || conflict.ExpectedRevision != stale {
+			t.Errorf("server precondition %v: unexpected conflict %+v", serverSide, conflict)
+		}
+		if !serverSide && conflict.ActualRevision != DocumentRevision(updated.Meta) {
+			t.Errorf("Expected actual revision %s, got %s", DocumentRevision(updated.Meta), conflict.ActualRevision)
+		}
+	}
+
+	if current, _ := server.Document("", "task", doc.ID); current.Data["title"] != "First" {
+		t.Errorf("Expected stale updates to be rejected, got title %v", current.Data["title"])
+	}
+
+	// The server precondition is only declared when used
+	for _, req := range server.Requests() {
+		_, sent := req.Variables["expected_revision"]
+		if req.OperationName == "UpdateModelData" && sent != strings.Contains(req.Query, "$expected_revision") {
+			t.Errorf("expected_revision sent %v but query is: %s", sent, req.Query)
+		}
+	}
+}
+
+func TestRetryOnConflict(t *testing.T) {
+	server := apitotest.NewServer()
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	seeded := server.Seed("", "counter", map[string]interface{}{"value": 1, "note": "keep"})
+
+	for _, serverSide := range []bool{false, true} {
+		raced := server.Seed("", "counter", map[string]interface{}{"value": 1, "note": "keep"})
+
+		calls := 0
+		doc, err := client.RetryOnConflict(ctx, "counter", raced.ID, func(doc *types.DefaultDocumentStructure) error {
+			calls++
+			if calls == 1 {
+				// Another writer updates the document between the read and the write
+				if _, err := client.UpdateResource(ctx, &types.CreateAndUpdateRequest{ID: raced.ID, Model: "counter", Payload: map[string]interface{}{"value": 10}}); err != nil {
+					t.Fatalf("concurrent update failed: %v", err)
+				}
+			}
+			doc.Data["value"] = doc.Data["value"].(float64) + 1
+			return nil
+		}, RetryOnConflictOptions{ServerPrecondition: serverSide})
+		if err != nil {
+			t.Fatalf("server precondition %v: RetryOnConflict failed: %v", serverSide, err)
+		}
+		if calls != 2 {
+			t.Errorf("server precondition %v: expected mutate to be reapplied once, got %d calls", serverSide, calls)
+		}
+		if doc.Data["value"] != float64(11) || doc.Data["note"] != "keep" {
+			t.Errorf("server precondition %v: expected the mutation applied to the fresh document, got %v", serverSide, doc.Data)
+		}
+	}
+
+	// Each cycle reads the document once, and once more to check the revision client-side
+	for serverSide, want := range map[bool]int{false: 2, true: 1} {
+		before := len(server.Requests())
+		if _, err := client.RetryOnConflict(ctx, "counter", seeded.ID, func(doc *types.DefaultDocumentStructure) error {
+			doc.Data["value"] = doc.Data["value"].(float64) + 1
+			return nil
+		}, RetryOnConflictOptions{ServerPrecondition: serverSide}); err != nil {
+			t.Fatalf("RetryOnConflict failed: %v", err)
+		}
+		var reads int
+		for _, req := range server.Requests()[before:] {
+			if req.OperationName == "GetSingleData" {
+				reads++
+			}
+		}
+		if reads != want {
+			t.Errorf("server precondition %v: expected %d reads, got %d", serverSide, want, reads)
+		}
+	}
+
+	errAbort := errors.New("abort")
+	if _, err := client.RetryOnConflict(ctx, "counter", seeded.ID, func(*types.DefaultDocumentStructure) error { return errAbort }, RetryOnConflictOptions{}); err != errAbort {
+		t.Errorf("Expected the mutate error, got %v", err)
+	}
+
+	if _, err := client.RetryOnConflict(ctx, "counter", "missing", func(*types.DefaultDocumentStructure) error { return nil }, RetryOnConflictOptions{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRetryOnConflictGivesUp(t *testing.T) {
+	server := apitotest.NewServer()
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	seeded := server.Seed("", "counter", map[string]interface{}{"value": 1})
+
+	for _, serverSide := range []bool{false, true} {
+		_, err := client.RetryOnConflict(ctx, "counter", seeded.ID, func(doc *types.DefaultDocumentStructure) error {
+			// Every attempt loses the race
+			if _, err := client.UpdateResource(ctx, &types.CreateAndUpdateRequest{ID: seeded.ID, Model: "counter", Payload: map[string]interface{}{"value": 0}}); err != nil {
+				t.Fatalf("concurrent update failed: %v", err)
+			}
+			return nil
+		}, RetryOnConflictOptions{ServerPrecondition: serverSide})
+
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) || retryErr.Attempts != conflictRetryAttempts || !errors.Is(err, ErrConflict) {
+			t.Errorf("server precondition %v: expected a RetryError wrapping a conflict after %d attempts, got %v", serverSide, conflictRetryAttempts, err)
+		}
+	}
+}
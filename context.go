@@ -0,0 +1,104 @@
+package goapitosdk
+
+import (
+	"context"
+	"net/http"
+)
+
+// contextKey is the type of the context keys defined by this package
+type contextKey int
+
+const (
+	tenantKey contextKey = iota
+	apiKeyKey
+	localeKey
+	requestIDKey
+	headersKey
+	fieldsKey
+)
+
+// legacyTenantKey is the untyped key read before WithTenant existed.
+//
+// Deprecated: use WithTenant. The legacy key is still honoured for now and will be removed in a future major version.
+const legacyTenantKey = "tenant_id"
+
+// WithTenant returns a context whose calls are scoped to the given tenant (X-Apito-Tenant-ID header)
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenantID)
+}
+
+// TenantFromContext returns the tenant set with WithTenant, falling back to the legacy
+// "tenant_id" string key. Values of the legacy key that are not strings are ignored.
+func TenantFromContext(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(tenantKey).(string); ok {
+		return tenantID
+	}
+	tenantID, _ := ctx.Value(legacyTenantKey).(string)
+	return tenantID
+}
+
+// WithAPIKey returns a context whose calls use apiKey instead of the client API key
+func WithAPIKey(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, apiKeyKey, apiKey)
+}
+
+// APIKeyFromContext returns the API key set with WithAPIKey, if any
+func APIKeyFromContext(ctx context.Context) string {
+	apiKey, _ := ctx.Value(apiKeyKey).(string)
+	return apiKey
+}
+
+// WithLocale returns a context whose calls request data in the given locale
+// (Accept-Language header, and the locale of searches that do not set one)
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey, locale)
+}
+
+// LocaleFromContext returns the locale set with WithLocale, if any
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey).(string)
+	return locale
+}
+
+// WithRequestID returns a context whose calls carry the given X-Request-ID header
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID set with WithRequestID, if any
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithHeader returns a context whose calls carry an extra HTTP header.
+// Headers accumulate across nested calls; a later value replaces an earlier one for the same key.
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	headers := HeadersFromContext(ctx)
+	headers.Set(key, value)
+	return context.WithValue(ctx, headersKey, headers)
+}
+
+// HeadersFromContext returns a copy of the extra headers set with WithHeader
+func HeadersFromContext(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(headersKey).(http.Header)
+	if headers == nil {
+		return make(http.Header)
+	}
+	return headers.Clone()
+}
+
+// requestHeaders returns the per-call headers carried by the context
+func requestHeaders(ctx context.Context) http.Header {
+	headers := HeadersFromContext(ctx)
+	if apiKey := APIKeyFromContext(ctx); apiKey != "" {
+		headers.Set("X-Apito-Key", apiKey)
+	}
+	if locale := LocaleFromContext(ctx); locale != "" {
+		headers.Set("Accept-Language", locale)
+	}
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		headers.Set("X-Request-ID", requestID)
+	}
+	return headers
+}
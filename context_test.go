@@ -0,0 +1,81 @@
+package goapitosdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type legacyKeyTest string
+
+func TestTenantFromContext(t *testing.T) {
+	ctx := context.Background()
+	if got := TenantFromContext(ctx); got != "" {
+		t.Errorf("Expected no tenant, got %q", got)
+	}
+
+	// The deprecated string key is still honoured
+	legacy := context.WithValue(ctx, "tenant_id", "legacy-tenant")
+	if got := TenantFromContext(legacy); got != "legacy-tenant" {
+		t.Errorf("Expected legacy tenant, got %q", got)
+	}
+
+	if got := TenantFromContext(WithTenant(legacy, "typed-tenant")); got != "typed-tenant" {
+		t.Errorf("Expected typed tenant to win, got %q", got)
+	}
+
+	// A non-string legacy value must not panic
+	invalid := context.WithValue(ctx, "tenant_id", 42)
+	if got := TenantFromContext(invalid); got != "" {
+		t.Errorf("Expected non-string legacy value to be ignored, got %q", got)
+	}
+
+	// A key of another type with the same underlying string must not collide
+	other := context.WithValue(ctx, legacyKeyTest("tenant_id"), "other")
+	if got := TenantFromContext(other); got != "" {
+		t.Errorf("Expected foreign key to be ignored, got %q", got)
+	}
+}
+
+func TestContextHeadersAreSent(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte(`{"data":{"debug":{"message":"ok"}}}`))
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "client-key"})
+
+	ctx := WithTenant(context.Background(), "tenant-1")
+	ctx = WithAPIKey(ctx, "call-key")
+	ctx = WithLocale(ctx, "bn")
+	ctx = WithRequestID(ctx, "req-42")
+	ctx = WithHeader(ctx, "X-Trace", "a")
+	ctx = WithHeader(ctx, "X-Other", "b")
+
+	if _, err := client.Debug(ctx, "stage"); err != nil {
+		t.Fatalf("Debug failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"X-Apito-Tenant-Id": "tenant-1",
+		"X-Apito-Key":       "call-key",
+		"Accept-Language":   "bn",
+		"X-Request-Id":      "req-42",
+		"X-Trace":           "a",
+		"X-Other":           "b",
+	}
+	for key, value := range expected {
+		if got.Get(key) != value {
+			t.Errorf("Expected header %s=%q, got %q", key, value, got.Get(key))
+		}
+	}
+
+	if _, err := client.Debug(context.Background(), "stage"); err != nil {
+		t.Fatalf("Debug failed: %v", err)
+	}
+	if got.Get("X-Apito-Key") != "client-key" || got.Get("X-Trace") != "" {
+		t.Errorf("Expected per-call values not to leak into other calls, got %v", got)
+	}
+}
@@ -0,0 +1,135 @@
+package goapitosdk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialProvider supplies the API key sent with every request.
+// It is consulted on each call, so a provider may return a different key over time.
+type CredentialProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// CredentialRefresher is implemented by providers that can reload their credential on demand.
+// The client calls Refresh when the server rejects a request as unauthorized.
+type CredentialRefresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// CredentialFunc adapts a function to a CredentialProvider
+type CredentialFunc func(ctx context.Context) (string, error)
+
+// APIKey calls f(ctx)
+func (f CredentialFunc) APIKey(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticCredentials returns a provider that always returns the same API key
+func StaticCredentials(apiKey string) CredentialProvider {
+	return staticCredentials(apiKey)
+}
+
+type staticCredentials string
+
+func (s staticCredentials) APIKey(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// EnvCredentials returns a provider reading the API key from an environment variable on every call
+func EnvCredentials(name string) CredentialProvider {
+	return envCredentials(name)
+}
+
+type envCredentials string
+
+func (e envCredentials) APIKey(ctx context.Context) (string, error) {
+	apiKey := strings.TrimSpace(os.Getenv(string(e)))
+	if apiKey == "" {
+		return "", fmt.Errorf("environment variable %s is not set", string(e))
+	}
+	return apiKey, nil
+}
+
+// FileCredentials reads the API key from a file, such as a mounted Kubernetes secret,
+// and reloads it when the file changes. Surrounding whitespace is trimmed.
+type FileCredentials struct {
+	path     string
+	interval time.Duration
+
+	mu        sync.Mutex
+	apiKey    string
+	modTime   time.Time
+	size      int64
+	checkedAt time.Time
+}
+
+// NewFileCredentials creates a provider reading the API key from path. The file is checked
+// for changes at most once per interval (default: 10 seconds). The file is read immediately
+// so that a missing or empty file is reported at startup.
+func NewFileCredentials(path string, interval time.Duration) (*FileCredentials, error) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	f := &FileCredentials{path: path, interval: interval}
+	if err := f.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// APIKey returns the current key, reloading the file if it changed since the last check.
+// While the file is missing or unreadable, such as during a secret rotation, the key last
+// loaded keeps being returned; Refresh reports the reload error.
+func (f *FileCredentials) APIKey(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if time.Since(f.checkedAt) >= f.interval {
+		if err := f.reload(false); err != nil && f.apiKey == "" {
+			return "", err
+		}
+	}
+	return f.apiKey, nil
+}
+
+// Refresh reloads the file unconditionally and reports why it could not be read
+func (f *FileCredentials) Refresh(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.reload(true)
+}
+
+// reload reads the file when forced or when its modification time or size changed.
+// The previous key is kept if the file cannot be read.
+func (f *FileCredentials) reload(force bool) error {
+	f.checkedAt = time.Now()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat credential file: %w", err)
+	}
+	if !force && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return nil
+	}
+
+	content, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to read credential file: %w", err)
+	}
+	apiKey := strings.TrimSpace(string(content))
+	if apiKey == "" {
+		return fmt.Errorf("credential file %s is empty", f.path)
+	}
+
+	f.apiKey = apiKey
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return nil
+}
@@ -0,0 +1,190 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newKeyServer accepts only requests carrying the current value of validKey
+func newKeyServer(t *testing.T, validKey *atomic.Value, calls *atomic.Int64) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("X-Apito-Key") != validKey.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":{"debug":{"message":"ok"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCredentialProviderConsultedPerRequest(t *testing.T) {
+	var validKey atomic.Value
+	var calls atomic.Int64
+	validKey.Store("key-1")
+	server := newKeyServer(t, &validKey, &calls)
+
+	var current atomic.Value
+	current.Store("key-1")
+	client := NewClient(Config{
+		BaseURL: server.URL,
+		Credentials: CredentialFunc(func(ctx context.Context) (string, error) {
+			return current.Load().(string), nil
+		}),
+	})
+
+	if _, err := client.Debug(context.Background(), "stage"); err != nil {
+		t.Fatalf("Debug failed: %v", err)
+	}
+
+	validKey.Store("key-2")
+	current.Store("key-2")
+	if _, err := client.Debug(context.Background(), "stage"); err != nil {
+		t.Fatalf("Expected the rotated key to be used, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls.Load())
+	}
+}
+
+func TestCredentialRefreshOnUnauthorized(t *testing.T) {
+	var validKey atomic.Value
+	var calls atomic.Int64
+	validKey.Store("old-key")
+	server := newKeyServer(t, &validKey, &calls)
+
+	path := filepath.Join(t.TempDir(), "apito-key")
+	if err := os.WriteFile(path, []byte("old-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	credentials, err := NewFileCredentials(path, time.Hour)
+	if err != nil {
+		t.Fatalf("NewFileCredentials failed: %v", err)
+	}
+	client := NewClient(Config{BaseURL: server.URL, Credentials: credentials})
+
+	if _, err := client.Debug(context.Background(), "stage"); err != nil {
+		t.Fatalf("Debug failed: %v", err)
+	}
+
+	// Rotated on disk, but the provider has not checked the file again yet
+	validKey.Store("new-key")
+	if err := os.WriteFile(path, []byte("new-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	calls.Store(0)
+	if _, err := client.Debug(context.Background(), "stage"); err != nil {
+		t.Fatalf("Expected a retry with the refreshed key, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls.Load())
+	}
+
+	// A key that does not change is not retried
+	validKey.Store("other-key")
+	calls.Store(0)
+	if _, err := client.Debug(context.Background(), "stage"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 call, got %d", calls.Load())
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	provider := EnvCredentials("APITO_TEST_KEY")
+
+	t.Setenv("APITO_TEST_KEY", "")
+	if _, err := provider.APIKey(context.Background()); err == nil {
+		t.Error("Expected an error for an unset variable")
+	}
+
+	t.Setenv("APITO_TEST_KEY", " env-key ")
+	if key, err := provider.APIKey(context.Background()); err != nil || key != "env-key" {
+		t.Errorf("Expected env-key, got %q (%v)", key, err)
+	}
+}
+
+func TestCredentialErrorAndContextOverride(t *testing.T) {
+	var validKey atomic.Value
+	var calls atomic.Int64
+	validKey.Store("call-key")
+	server := newKeyServer(t, &validKey, &calls)
+
+	failing := CredentialFunc(func(ctx context.Context) (string, error) {
+		return "", errors.New("vault unavailable")
+	})
+	client := NewClient(Config{BaseURL: server.URL, Credentials: failing})
+
+	if _, err := client.Debug(context.Background(), "stage"); err == nil || calls.Load() != 0 {
+		t.Errorf("Expected the provider error without a request, got %v after %d calls", err, calls.Load())
+	}
+
+	// A key set on the context bypasses the provider
+	if _, err := client.Debug(WithAPIKey(context.Background(), "call-key"), "stage"); err != nil {
+		t.Errorf("Expected the context key to be used, got %v", err)
+	}
+}
+
+func TestNewFileCredentialsErrors(t *testing.T) {
+	if _, err := NewFileCredentials(filepath.Join(t.TempDir(), "missing"), 0); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+
+	path := filepath.Join(t.TempDir(), "empty")
+	os.WriteFile(path, []byte("  \n"), 0o600)
+	if _, err := NewFileCredentials(path, 0); err == nil {
+		t.Error("Expected an error for an empty file")
+	}
+}
+
+func TestFileCredentialsKeepKeyWhileFileUnavailable(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "apito-key")
+	if err := os.WriteFile(path, []byte("key-1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	credentials, err := NewFileCredentials(path, time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewFileCredentials failed: %v", err)
+	}
+
+	// Removed between checks, as while a mounted secret is swapped
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if apiKey, err := credentials.APIKey(ctx); err != nil || apiKey != "key-1" {
+		t.Errorf("Expected the cached key, got %q (%v)", apiKey, err)
+	}
+	if err := credentials.Refresh(ctx); err == nil {
+		t.Error("Expected Refresh to report the missing file")
+	}
+
+	// Replaced by an empty file
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if apiKey, err := credentials.APIKey(ctx); err != nil || apiKey != "key-1" {
+		t.Errorf("Expected the cached key, got %q (%v)", apiKey, err)
+	}
+
+	// Replaced by a new key
+	if err := os.WriteFile(path, []byte("key-2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if apiKey, err := credentials.APIKey(ctx); err != nil || apiKey != "key-2" {
+		t.Errorf("Expected the new key, got %q (%v)", apiKey, err)
+	}
+}
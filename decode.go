@@ -0,0 +1,262 @@
+package goapitosdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/apito-io/types"
+)
+
+// decodeGraphQLResponse decodes a GraphQL response in a single pass over r.
+//
+// Data fields listed in targets are decoded straight into their target, without going
+// through generic maps first. A target must be a pointer to a pointer, map or slice so
+// that a null field can be told apart from an empty one. The other data fields are decoded
+// into generic values. Data is a map[string]interface{} holding the decoded values, the
+// target ones included, or nil when the server returned no data.
+//
+// A target that does not match the shape of its field is reported as a *DecodeError
+// for that field once the whole response has been read, together with the response.
+func decodeGraphQLResponse(r io.Reader, targets map[string]interface{}) (*types.GraphQLResponse, error) {
+	dec := json.NewDecoder(r)
+	response := &types.GraphQLResponse{}
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, &DecodeError{Err: err}
+	}
+
+	var fieldErr error
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, &DecodeError{Err: err}
+		}
+
+		switch key {
+		case "data":
+			data, err := decodeData(dec, targets, &fieldErr)
+			if err != nil {
+				return nil, err
+			}
+			if data != nil {
+				response.Data = data
+			}
+		case "errors":
+			if err := dec.Decode(&response.Errors); err != nil {
+				return nil, &DecodeError{Field: "errors", Err: err}
+			}
+		default:
+			// Extensions and other members are not used by the SDK
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return nil, &DecodeError{Err: err}
+			}
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, &DecodeError{Err: err}
+	}
+
+	return response, fieldErr
+}
+
+// decodeData decodes the data object of a response, recording the first target mismatch in fieldErr
+func decodeData(dec *json.Decoder, targets map[string]interface{}, fieldErr *error) (map[string]interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, &DecodeError{Err: err}
+	}
+	if token == nil {
+		return nil, nil
+	}
+	if token != json.Delim('{') {
+		return nil, &DecodeError{Err: errUnexpectedFormat}
+	}
+
+	data := make(map[string]interface{}, len(targets))
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, &DecodeError{Err: err}
+		}
+		field, _ := token.(string)
+
+		target, ok := targets[field]
+		if !ok {
+			var value interface{}
+			if err := dec.Decode(&value); err != nil {
+				return nil, &DecodeError{Field: field, Err: err}
+			}
+			data[field] = value
+			continue
+		}
+
+		// The target is reset so that a retried round-trip does not merge into a previous attempt
+		value := reflect.ValueOf(target).Elem()
+		value.SetZero()
+		if err := decodeTarget(dec, target); err != nil {
+			// A type mismatch leaves the stream in sync, anything else does not
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return nil, &DecodeError{Field: field, Err: err}
+			}
+			if *fieldErr == nil {
+				*fieldErr = &DecodeError{Field: field, Err: err}
+			}
+		}
+
+		if isNilValue(value) {
+			data[field] = nil
+		} else {
+			data[field] = value.Interface()
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, &DecodeError{Err: err}
+	}
+	return data, nil
+}
+
+// expectDelim reads the next token and checks it is the given delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %q, got %v: %w", delim, token, errUnexpectedFormat)
+	}
+	return nil
+}
+
+// isNilValue reports whether v holds a nil pointer, map, slice or interface
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// countingReader counts the bytes read from r and keeps the first read error other than io.EOF
+type countingReader struct {
+	r   io.Reader
+	n   int
+	err error
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	if err != nil && err != io.EOF && c.err == nil {
+		c.err = err
+	}
+	return n, err
+}
+
+// decodeTarget decodes the next value of dec into target. Untyped documents and search
+// results are decoded through untypedDocument, as their expire_at may be a number.
+func decodeTarget(dec *json.Decoder, target interface{}) error {
+	switch t := target.(type) {
+	case **types.DefaultDocumentStructure:
+		var doc *untypedDocument
+		err := dec.Decode(&doc)
+		*t = doc.untyped()
+		return err
+	case *[]*types.DefaultDocumentStructure:
+		var docs []*untypedDocument
+		err := dec.Decode(&docs)
+		*t = untypedDocuments(docs)
+		return err
+	case **types.SearchResult:
+		var result *struct {
+			Results []*untypedDocument `json:"results"`
+			Count   int                `json:"count"`
+		}
+		err := dec.Decode(&result)
+		if result != nil {
+			*t = &types.SearchResult{Results: untypedDocuments(result.Results), Count: result.Count}
+		}
+		return err
+	}
+	return dec.Decode(target)
+}
+
+// untypedDocument decodes a types.DefaultDocumentStructure, taking expire_at as a string or a number
+type untypedDocument struct {
+	types.DefaultDocumentStructure
+	ExpireAt expireAtValue `json:"expire_at,omitempty"`
+}
+
+// untyped converts the decoded document to its public form, keeping nil as nil
+func (d *untypedDocument) untyped() *types.DefaultDocumentStructure {
+	if d == nil {
+		return nil
+	}
+	doc := d.DefaultDocumentStructure
+	doc.ExpireAt = string(d.ExpireAt)
+	return &doc
+}
+
+// untypedDocuments converts decoded documents, keeping a nil slice as nil
+func untypedDocuments(docs []*untypedDocument) []*types.DefaultDocumentStructure {
+	if docs == nil {
+		return nil
+	}
+	result := make([]*types.DefaultDocumentStructure, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.untyped()
+	}
+	return result
+}
+
+// typedDocument mirrors types.DefaultDocumentStructure with typed data. Documents are
+// decoded into it directly and then converted, since the expire_at field of
+// types.TypedDocumentStructure does not have the wire format.
+type typedDocument[T any] struct {
+	Key           string           `json:"_key,omitempty"`
+	ID            string           `json:"id,omitempty"`
+	Type          string           `json:"type,omitempty"`
+	Data          T                `json:"data,omitempty"`
+	Meta          *types.MetaField `json:"meta,omitempty"`
+	ExpireAt      expireAtValue    `json:"expire_at,omitempty"`
+	RelationDocID string           `json:"relation_doc_id,omitempty"`
+}
+
+// typed converts the decoded document to its public form
+func (d *typedDocument[T]) typed() *types.TypedDocumentStructure[T] {
+	return &types.TypedDocumentStructure[T]{
+		Key:           d.Key,
+		Data:          d.Data,
+		Meta:          d.Meta,
+		ID:            d.ID,
+		ExpireAt:      parseExpireAt(string(d.ExpireAt)),
+		RelationDocID: d.RelationDocID,
+		Type:          d.Type,
+	}
+}
+
+// typedSearchResult mirrors types.SearchResult with typed documents
+type typedSearchResult[T any] struct {
+	Results []*typedDocument[T] `json:"results"`
+	Count   int                 `json:"count"`
+}
+
+// typed converts the decoded search result to its public form
+func (r *typedSearchResult[T]) typed() *types.TypedSearchResult[T] {
+	result := &types.TypedSearchResult[T]{
+		Count:   r.Count,
+		Results: make([]*types.TypedDocumentStructure[T], len(r.Results)),
+	}
+	for i, doc := range r.Results {
+		if doc != nil {
+			result.Results[i] = doc.typed()
+		}
+	}
+	return result
+}
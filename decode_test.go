@@ -0,0 +1,224 @@
+package goapitosdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/apito-io/types"
+)
+
+func TestDecodeGraphQLResponse(t *testing.T) {
+	body := `{"data":{"getModelData":{"count":2,"results":[{"id":"a","data":{"title":"A"}},{"id":"b"}]},"other":{"n":1},"missing":null},"extensions":{"cost":3}}`
+
+	var result *types.SearchResult
+	var missing *types.DefaultDocumentStructure
+	response, err := decodeGraphQLResponse(strings.NewReader(body), map[string]interface{}{
+		"getModelData": &result,
+		"missing":      &missing,
+	})
+	if err != nil {
+		t.Fatalf("decodeGraphQLResponse failed: %v", err)
+	}
+
+	if result == nil || result.Count != 2 || len(result.Results) != 2 || result.Results[0].Data["title"] != "A" {
+		t.Fatalf("Unexpected target: %+v", result)
+	}
+	data := response.Data.(map[string]interface{})
+	if data["getModelData"] != result {
+		t.Error("Expected the data to hold the decoded target")
+	}
+	if other, ok := data["other"].(map[string]interface{}); !ok || other["n"] != float64(1) {
+		t.Errorf("Expected a generic value for fields without target, got %#v", data["other"])
+	}
+
+	if err := decodeResponseField(response, "missing", &missing); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a null field, got %v", err)
+	}
+	var copied *types.SearchResult
+	if err := decodeResponseField(response, "getModelData", &copied); err != nil || copied != result {
+		t.Errorf("Expected the decoded target to be reused, got %v (%v)", copied, err)
+	}
+}
+
+func TestDecodeGraphQLResponseErrors(t *testing.T) {
+	t.Run("type mismatch", func(t *testing.T) {
+		var result *types.SearchResult
+		response, err := decodeGraphQLResponse(strings.NewReader(`{"data":{"getModelData":{"count":"many"}},"errors":[{"message":"partial"}]}`),
+			map[string]interface{}{"getModelData": &result})
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Field != "getModelData" {
+			t.Fatalf("Expected a DecodeError for getModelData, got %v", err)
+		}
+		if response == nil || len(response.Errors) != 1 {
+			t.Error("Expected the rest of the response to be decoded")
+		}
+	})
+
+	for name, body := range map[string]string{
+		"truncated": `{"data":{"getModelData":{"count":1`,
+		"not json":  `<html>`,
+		"array":     `{"data":[1,2]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var result *types.SearchResult
+			_, err := decodeGraphQLResponse(strings.NewReader(body), map[string]interface{}{"getModelData": &result})
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Errorf("Expected a DecodeError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTypedReadFromInterceptorResponse(t *testing.T) {
+	// Responses built by interceptors hold generic values and go through the JSON conversion
+	config := Config{BaseURL: "http://apito.invalid", APIKey: "test-key"}
+	config.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, op *Operation) (*types.GraphQLResponse, error) {
+			return &types.GraphQLResponse{Data: map[string]interface{}{
+				"getSingleData": map[string]interface{}{"id": "t1", "data": map[string]interface{}{"title": "Cached"}},
+			}}, nil
+		}
+	})
+	client := NewClient(config)
+
+	doc, err := GetSingleResourceTyped[testTask](client, context.Background(), "task", "t1", false)
+	if err != nil {
+		t.Fatalf("GetSingleResourceTyped failed: %v", err)
+	}
+	if doc.ID != "t1" || doc.Data.Title != "Cached" {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+}
+
+func TestTypedReadDecodesData(t *testing.T) {
+	server := newStaticServer(t, http.StatusOK, `{"data":{"getModelData":{"count":1,"results":[{"id":"t1","type":"task","data":{"title":"Write docs","done":true},"meta":{"status":"published"}}]}}}`)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	result, err := SearchTyped[testTask](client, context.Background(), "task", SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchTyped failed: %v", err)
+	}
+	if result.Count != 1 || len(result.Results) != 1 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	doc := result.Results[0]
+	if doc.ID != "t1" || doc.Type != "task" || doc.Data.Title != "Write docs" || !doc.Data.Done || doc.Meta.Status != "published" {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+
+	if _, err := SearchTyped[struct{ Title int }](client, context.Background(), "task", SearchOptions{}); err == nil {
+		t.Error("Expected a decode error for mismatched data")
+	}
+}
+
+type testTask struct {
+	Title string `json:"title"`
+	Done  bool   `json:"done"`
+}
+
+type benchProduct struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Price       float64  `json:"price"`
+	Stock       int      `json:"stock"`
+	Tags        []string `json:"tags"`
+}
+
+// benchmarkPage returns a getModelData response of n documents
+func benchmarkPage(n int) []byte {
+	results := make([]map[string]interface{}, n)
+	for i := range results {
+		results[i] = map[string]interface{}{
+			"id":        fmt.Sprintf("product-%d", i),
+			"type":      "product",
+			"expire_at": "",
+			"data": map[string]interface{}{
+				"name":        fmt.Sprintf("Product %d", i),
+				"description": strings.Repeat("lorem ipsum ", 8),
+				"price":       float64(i) * 1.5,
+				"stock":       i,
+				"tags":        []string{"new", "sale", "featured"},
+			},
+			"meta": map[string]interface{}{"created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-02T00:00:00Z", "status": "published"},
+		}
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"data": map[string]interface{}{"getModelData": map[string]interface{}{"count": n, "results": results}},
+	})
+	return body
+}
+
+// legacyDecode is the decode path used before single-pass decoding: generic decode,
+// then a marshal and unmarshal round trip into the target
+func legacyDecode(body []byte, field string, target interface{}) error {
+	response := &types.GraphQLResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return err
+	}
+	raw := response.Data.(map[string]interface{})[field]
+	rawJSON, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(rawJSON, target)
+}
+
+func BenchmarkDecodeModelData(b *testing.B) {
+	body := benchmarkPage(500)
+
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			var result types.SearchResult
+			if err := legacyDecode(body, "getModelData", &result); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("single-pass", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			var result *types.SearchResult
+			if _, err := decodeGraphQLResponse(bytes.NewReader(body), map[string]interface{}{"getModelData": &result}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("typed-legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			var result types.SearchResult
+			if err := legacyDecode(body, "getModelData", &result); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := convertToTypedSearchResult[benchProduct](&result); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("typed-single-pass", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			var result *typedSearchResult[benchProduct]
+			if _, err := decodeGraphQLResponse(bytes.NewReader(body), map[string]interface{}{"getModelData": &result}); err != nil {
+				b.Fatal(err)
+			}
+			result.typed()
+		}
+	})
+}
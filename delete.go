@@ -0,0 +1,200 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/apito-io/types"
+)
+
+// Document statuses reported by delete and restore operations
+const (
+	StatusDeleted = "deleted"
+	StatusTrashed = "trashed"
+)
+
+// ErrCountMismatch is returned by DeleteWhere when the number of matching documents
+// differs from the count confirmed by the caller
+var ErrCountMismatch = errors.New("matching document count changed since dry run")
+
+// DeleteOptions configures a delete
+type DeleteOptions struct {
+	Soft bool // Move the document to the trash instead of deleting it permanently; see RestoreResource
+}
+
+// DeleteResult describes a deleted or restored document
+type DeleteResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"` // StatusDeleted, StatusTrashed or the restored status
+}
+
+// DeleteWhereOptions configures a filter-based delete
+type DeleteWhereOptions struct {
+	Soft bool // Move the documents to the trash instead of deleting them permanently
+
+	// DryRun only counts the matching documents. Run a dry run first and pass its count as
+	// ExpectedCount to perform the delete; the delete is refused if the count has changed.
+	DryRun        bool
+	ExpectedCount int
+
+	Bulk BulkOptions // Chunking and concurrency of the delete mutations
+}
+
+// DeleteWhereResult reports the outcome of a filter-based delete
+type DeleteWhereResult struct {
+	Matched int         // Documents matching the filter
+	DryRun  bool        // True when nothing was deleted
+	Report  *BulkResult // Per-document report, nil for dry runs
+}
+
+// deleteSelection is the selection set returned by delete and restore mutations
+const deleteSelection = `{
+				id
+				status
+			}`
+
+// Delete deletes a resource by model and ID and returns the deleted document ID and status
+func (c *Client) Delete(ctx context.Context, model, _id string, opts DeleteOptions) (*DeleteResult, error) {
+	if model == "" {
+		return nil, newValidationError("model is required")
+	}
+	if _id == "" {
+		return nil, newValidationError("id is required")
+	}
+
+	softDeclaration, softArg := "", ""
+	variables := map[string]interface{}{
+		"model": model,
+		"_id":   _id,
+	}
+	if opts.Soft {
+		softDeclaration, softArg = ", $soft_delete: Boolean", ", soft_delete: $soft_delete"
+		variables["soft_delete"] = true
+	}
+
+	query := fmt.Sprintf(`
+		mutation DeleteData($model: String!, $_id: String!%s) {
+			deleteModelData(model_name: $model, _id: $_id%s) %s
+		}
+	`, softDeclaration, softArg, deleteSelection)
+
+	response, err := c.executeGraphQL(ctx, query, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete resource: %w", err)
+	}
+
+	result, err := decodeDeleteResult(response, "deleteModelData", _id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete resource: %w", err)
+	}
+	if result.Status == "" {
+		result.Status = StatusDeleted
+		if opts.Soft {
+			result.Status = StatusTrashed
+		}
+	}
+
+	return result, nil
+}
+
+// RestoreResource restores a soft-deleted resource from the trash
+func (c *Client) RestoreResource(ctx context.Context, model, _id string) (*DeleteResult, error) {
+	if model == "" {
+		return nil, newValidationError("model is required")
+	}
+	if _id == "" {
+		return nil, newValidationError("id is required")
+	}
+
+	query := fmt.Sprintf(`
+		mutation RestoreData($model: String!, $_id: String!) {
+			restoreModelData(model_name: $model, _id: $_id) %s
+		}
+	`, deleteSelection)
+
+	variables := map[string]interface{}{
+		"model": model,
+		"_id":   _id,
+	}
+
+	response, err := c.executeGraphQL(ctx, query, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore resource: %w", err)
+	}
+
+	result, err := decodeDeleteResult(response, "restoreModelData", _id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore resource: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteWhere deletes every document of a model matching the where filter.
+// A dry run (opts.DryRun) must be performed first: its Matched count has to be passed back as
+// opts.ExpectedCount, and the delete is refused with ErrCountMismatch if the count changed.
+// An empty filter is rejected to prevent accidentally wiping a model.
+func (c *Client) DeleteWhere(ctx context.Context, model string, where interface{}, opts DeleteWhereOptions) (*DeleteWhereResult, error) {
+	if model == "" {
+		return nil, newValidationError("model is required")
+	}
+	if isEmptyWhere(where) {
+		return nil, newValidationError("where filter is required for DeleteWhere")
+	}
+
+	matched, err := c.Count(ctx, model, SearchOptions{Where: where})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents to delete: %w", err)
+	}
+
+	result := &DeleteWhereResult{Matched: matched, DryRun: opts.DryRun}
+	if opts.DryRun {
+		return result, nil
+	}
+	if matched != opts.ExpectedCount {
+		return result, fmt.Errorf("%w: expected %d, found %d", ErrCountMismatch, opts.ExpectedCount, matched)
+	}
+
+	// Collect the IDs before deleting so that pagination is not affected by the deletes
+	var ids []string
+	for doc, err := range c.IterateResources(ctx, model, IterateOptions{Filter: map[string]interface{}{"where": where}, PageSize: 500, Prefetch: true}) {
+		if err != nil {
+			return result, fmt.Errorf("failed to list documents to delete: %w", err)
+		}
+		ids = append(ids, doc.ID)
+	}
+	if len(ids) != opts.ExpectedCount {
+		return result, fmt.Errorf("%w: expected %d, found %d", ErrCountMismatch, opts.ExpectedCount, len(ids))
+	}
+
+	result.Report, err = c.bulkDelete(ctx, model, ids, opts.Soft, opts.Bulk)
+	return result, err
+}
+
+// decodeDeleteResult decodes a delete or restore result and checks it refers to the requested document
+func decodeDeleteResult(response *types.GraphQLResponse, field, _id string) (*DeleteResult, error) {
+	var result DeleteResult
+	if err := decodeResponseField(response, field, &result); err != nil {
+		return nil, err
+	}
+	if result.ID == "" {
+		result.ID = _id
+	} else if result.ID != _id {
+		return nil, &DecodeError{Field: field, Err: fmt.Errorf("server returned id %q, expected %q", result.ID, _id)}
+	}
+	return &result, nil
+}
+
+// isEmptyWhere reports whether a where filter has no conditions
+func isEmptyWhere(where interface{}) bool {
+	switch w := where.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(w) == 0
+	case interface{ IsEmpty() bool }:
+		return w.IsEmpty()
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package goapitosdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apito-io/go-internal-sdk/where"
+)
+
+func TestDelete(t *testing.T) {
+	var requests []capturedRequest
+	server := newCapturingServer(t, `{"data":{"deleteModelData":{"id":"1","status":"trashed"}}}`, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	result, err := client.Delete(context.Background(), "task", "1", DeleteOptions{Soft: true})
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if result.ID != "1" || result.Status != StatusTrashed {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if requests[0].Variables["soft_delete"] != true || !strings.Contains(requests[0].Query, "soft_delete: $soft_delete") {
+		t.Errorf("Expected soft delete argument, got %s %v", requests[0].Query, requests[0].Variables)
+	}
+
+	if err := client.DeleteResource(context.Background(), "task", "1"); err != nil {
+		t.Errorf("DeleteResource failed: %v", err)
+	}
+	if strings.Contains(requests[1].Query, "soft_delete") {
+		t.Errorf("Expected hard delete without soft_delete argument:\n%s", requests[1].Query)
+	}
+}
+
+func TestDeleteVerifiesResponse(t *testing.T) {
+	server := newStaticServer(t, http.StatusOK, `{"data":{"deleteModelData":null}}`)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	if err := client.DeleteResource(context.Background(), "task", "1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	server = newStaticServer(t, http.StatusOK, `{"data":{"deleteModelData":{"id":"2"}}}`)
+	client = NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	var decodeErr *DecodeError
+	if err := client.DeleteResource(context.Background(), "task", "1"); !errors.As(err, &decodeErr) {
+		t.Errorf("Expected DecodeError for mismatched id, got %v", err)
+	}
+}
+
+func TestRestoreResource(t *testing.T) {
+	var requests []capturedRequest
+	server := newCapturingServer(t, `{"data":{"restoreModelData":{"id":"1","status":"draft"}}}`, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	result, err := client.RestoreResource(context.Background(), "task", "1")
+	if err != nil {
+		t.Fatalf("RestoreResource failed: %v", err)
+	}
+	if result.Status != "draft" || !strings.Contains(requests[0].Query, "restoreModelData") {
+		t.Errorf("Unexpected result %+v for query %s", result, requests[0].Query)
+	}
+}
+
+func TestDeleteWhere(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req capturedRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		switch {
+		case strings.Contains(req.Query, "BulkDelete"):
+			data := map[string]interface{}{}
+			for key, value := range req.Variables {
+				if strings.HasPrefix(key, "_id_") {
+					deleted = append(deleted, value.(string))
+					data["m"+strings.TrimPrefix(key, "_id_")] = map[string]interface{}{"id": value, "status": "deleted"}
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+		case !strings.Contains(req.Query, "results"):
+			w.Write([]byte(`{"data":{"getModelData":{"count":2}}}`))
+		default:
+			w.Write([]byte(`{"data":{"getModelData":{"results":[{"id":"a"},{"id":"b"}],"count":2}}}`))
+		}
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+	filter := where.Field("status").Eq("stale")
+
+	if _, err := client.DeleteWhere(ctx, "task", where.Expr{}, DeleteWhereOptions{DryRun: true}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected empty filter to be rejected, got %v", err)
+	}
+
+	dryRun, err := client.DeleteWhere(ctx, "task", filter, DeleteWhereOptions{DryRun: true})
+	if err != nil || dryRun.Matched != 2 || len(deleted) != 0 {
+		t.Fatalf("Unexpected dry run %+v (%v), deleted %v", dryRun, err, deleted)
+	}
+
+	if _, err := client.DeleteWhere(ctx, "task", filter, DeleteWhereOptions{ExpectedCount: 3}); !errors.Is(err, ErrCountMismatch) {
+		t.Errorf("Expected ErrCountMismatch, got %v", err)
+	}
+
+	result, err := client.DeleteWhere(ctx, "task", filter, DeleteWhereOptions{ExpectedCount: dryRun.Matched})
+	if err != nil {
+		t.Fatalf("DeleteWhere failed: %v", err)
+	}
+	if result.Report.Succeeded != 2 || len(deleted) != 2 {
+		t.Errorf("Expected 2 deletes, got %+v (%v)", result.Report, deleted)
+	}
+}
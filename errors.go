@@ -0,0 +1,164 @@
+package goapitosdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/apito-io/types"
+)
+
+// Sentinel errors usable with errors.Is on any error returned by the client
+var (
+	ErrNotFound     = errors.New("resource not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+	ErrConflict     = errors.New("revision conflict")
+	ErrUnsupported  = errors.New("operation not enabled for the server")
+)
+
+// errUnexpectedFormat is wrapped by DecodeError when the response does not have the expected shape
+var errUnexpectedFormat = errors.New("unexpected response format")
+
+// HTTPError is returned when the server answers with a non-200 status code
+type HTTPError struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, string(e.Body))
+}
+
+// Is maps the status code to the matching sentinel error
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	}
+	return false
+}
+
+// GraphQLError is a single error reported in the errors array of a GraphQL response
+type GraphQLError struct {
+	Message    string
+	Path       []interface{}
+	Locations  []types.GraphQLErrorLocation
+	Extensions map[string]interface{}
+	Code       string // extensions.code, when the server provides one
+}
+
+func (e *GraphQLError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+	}
+	return e.Message
+}
+
+// Is maps the extensions code to the matching sentinel error
+func (e *GraphQLError) Is(target error) bool {
+	switch strings.ToUpper(e.Code) {
+	case "NOT_FOUND":
+		return target == ErrNotFound
+	case "UNAUTHENTICATED", "UNAUTHORIZED":
+		return target == ErrUnauthorized
+	case "FORBIDDEN":
+		return target == ErrForbidden
+	case "RATE_LIMITED", "TOO_MANY_REQUESTS":
+		return target == ErrRateLimited
+	case "BAD_USER_INPUT", "GRAPHQL_VALIDATION_FAILED", "VALIDATION_ERROR":
+		return target == ErrValidation
+	case "CONFLICT", "REVISION_CONFLICT":
+		return target == ErrConflict
+	}
+	return false
+}
+
+// GraphQLErrors is returned when a GraphQL response carries one or more errors.
+// errors.As(err, &gqlErr) yields the first *GraphQLError and errors.Is checks every entry.
+type GraphQLErrors []*GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "GraphQL errors: " + strings.Join(messages, "; ")
+}
+
+func (e GraphQLErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// newGraphQLErrors converts the raw errors of a GraphQL response
+func newGraphQLErrors(raw []types.GraphQLError) GraphQLErrors {
+	errs := make(GraphQLErrors, len(raw))
+	for i, r := range raw {
+		code, _ := r.Extensions["code"].(string)
+		errs[i] = &GraphQLError{
+			Message:    r.Message,
+			Path:       r.Path,
+			Locations:  r.Locations,
+			Extensions: r.Extensions,
+			Code:       code,
+		}
+	}
+	return errs
+}
+
+// DecodeError is returned when a response cannot be decoded into the expected structure
+type DecodeError struct {
+	Field string // Response field being decoded, empty for the response envelope
+	Err   error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("failed to decode GraphQL response: %v", e.Err)
+	}
+	return fmt.Sprintf("failed to decode %s: %v", e.Field, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// validationError is returned when a request fails client-side validation
+type validationError struct {
+	message string
+	err     error
+}
+
+func newValidationError(format string, args ...interface{}) error {
+	return &validationError{message: fmt.Sprintf(format, args...)}
+}
+
+func (e *validationError) Error() string {
+	return e.message
+}
+
+func (e *validationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func (e *validationError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,129 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apito-io/go-internal-sdk/where"
+	"github.com/apito-io/types"
+)
+
+func newStaticServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPErrorSentinels(t *testing.T) {
+	cases := []struct {
+		status   int
+		sentinel error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusBadRequest, ErrValidation},
+		{http.StatusConflict, ErrConflict},
+	}
+
+	for _, c := range cases {
+		server := newStaticServer(t, c.status, "denied")
+		client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+		_, err := client.SearchResources(context.Background(), "task", nil, false)
+		if !errors.Is(err, c.sentinel) {
+			t.Errorf("status %d: expected errors.Is(%v), got %v", c.status, c.sentinel, err)
+		}
+
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) {
+			t.Fatalf("status %d: expected *HTTPError, got %T", c.status, err)
+		}
+		if httpErr.StatusCode != c.status || string(httpErr.Body) != "denied" {
+			t.Errorf("status %d: unexpected HTTPError %+v", c.status, httpErr)
+		}
+	}
+}
+
+func TestGraphQLErrorDetails(t *testing.T) {
+	server := newStaticServer(t, http.StatusOK, `{"errors":[{"message":"document missing","path":["getSingleData"],"locations":[{"line":2,"column":3}],"extensions":{"code":"NOT_FOUND"}}]}`)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	_, err := client.GetSingleResource(context.Background(), "task", "missing", false)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	var gqlErr *GraphQLError
+	if !errors.As(err, &gqlErr) {
+		t.Fatalf("Expected *GraphQLError, got %T", err)
+	}
+	if gqlErr.Message != "document missing" || gqlErr.Code != "NOT_FOUND" {
+		t.Errorf("Unexpected GraphQLError %+v", gqlErr)
+	}
+	if len(gqlErr.Locations) != 1 || gqlErr.Locations[0].Line != 2 {
+		t.Errorf("Expected locations to be preserved, got %+v", gqlErr.Locations)
+	}
+}
+
+func TestNullResultIsNotFound(t *testing.T) {
+	server := newStaticServer(t, http.StatusOK, `{"data":{"getSingleData":null}}`)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	_, err := client.GetSingleResource(context.Background(), "task", "missing", false)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	server := newStaticServer(t, http.StatusOK, `{"data":{"getModelData":{"count":"many"}}}`)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	_, err := client.SearchResources(context.Background(), "task", nil, false)
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Expected *DecodeError, got %v", err)
+	}
+	if decodeErr.Field != "getModelData" {
+		t.Errorf("Expected field getModelData, got %s", decodeErr.Field)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	client := NewClient(Config{BaseURL: "http://127.0.0.1:0", APIKey: "test-key"})
+
+	_, err := client.CreateNewResource(context.Background(), &types.CreateAndUpdateRequest{})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation, got %v", err)
+	}
+	if err.Error() != "model is required" {
+		t.Errorf("Expected message to be preserved, got %q", err.Error())
+	}
+}
+
+func TestInvalidWhereIsRejectedBeforeSending(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	filter := map[string]interface{}{"where": where.Field("status").In()}
+	_, err := client.SearchResources(context.Background(), "task", filter, false)
+	if !errors.Is(err, ErrValidation) || !errors.Is(err, where.ErrInvalid) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request to be sent, got %d", requests)
+	}
+}
@@ -51,7 +51,7 @@ func main() {
 	// Set up context with tenant ID if available
 	ctx := context.Background()
 	if tenantID := getEnv("APITO_TENANT_ID", ""); tenantID != "" {
-		ctx = context.WithValue(ctx, "tenant_id", tenantID)
+		ctx = goapitosdk.WithTenant(ctx, tenantID)
 	}
 
 	fmt.Println("🚀 Apito SDK Comprehensive Todo Example")
@@ -0,0 +1,57 @@
+package goapitosdk
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/apito-io/types"
+)
+
+// Operation describes a single GraphQL round-trip as seen by interceptors
+type Operation struct {
+	Name      string                 // Operation name, e.g. "GetModelData"
+	Type      string                 // Operation type: "query" or "mutation"
+	Query     string                 // GraphQL document sent to the server
+	Variables map[string]interface{} // Variables sent along with the document
+	TenantID  string                 // Tenant ID sent in the X-Apito-Tenant-ID header, if any
+	Header    http.Header            // Extra HTTP headers, applied after the default ones
+
+	targets map[string]interface{} // Data fields decoded straight into typed values, see decodeGraphQLResponse
+}
+
+// RoundTripFunc executes an operation and returns the decoded GraphQL response.
+// Response data is a map[string]interface{} keyed by root field; fields read by the
+// SDK methods hold the SDK types they were decoded into, e.g. *types.SearchResult,
+// rather than generic maps.
+type RoundTripFunc func(ctx context.Context, op *Operation) (*types.GraphQLResponse, error)
+
+// Interceptor wraps a RoundTripFunc to add behaviour around every GraphQL round-trip.
+// An interceptor may modify the operation before calling next, inspect or replace
+// the response and error, or short-circuit the call entirely.
+type Interceptor func(next RoundTripFunc) RoundTripFunc
+
+// Use appends interceptors to the configuration. Interceptors run in the order they were added.
+func (c *Config) Use(interceptors ...Interceptor) *Config {
+	c.Interceptors = append(c.Interceptors, interceptors...)
+	return c
+}
+
+// chainInterceptors builds a RoundTripFunc where the first interceptor is the outermost
+func chainInterceptors(interceptors []Interceptor, final RoundTripFunc) RoundTripFunc {
+	handler := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		if interceptors[i] != nil {
+			handler = interceptors[i](handler)
+		}
+	}
+	return handler
+}
+
+// withOuterInterceptors returns a copy of the client whose round-trips additionally run
+// through the given interceptors, placed outside the configured ones
+func (c *Client) withOuterInterceptors(interceptors ...Interceptor) *Client {
+	clone := *c
+	clone.interceptors = append(append([]Interceptor{}, interceptors...), c.interceptors...)
+	clone.handler = chainInterceptors(clone.interceptors, clone.send)
+	return &clone
+}
@@ -0,0 +1,118 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apito-io/types"
+)
+
+func TestInterceptorChainOrderAndHeaders(t *testing.T) {
+	var gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Request-ID")
+		w.Write([]byte(`{"data":{"debug":{"message":"ok"}}}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	record := func(name string) Interceptor {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(ctx context.Context, op *Operation) (*types.GraphQLResponse, error) {
+				order = append(order, name+":"+op.Name)
+				return next(ctx, op)
+			}
+		}
+	}
+
+	config := Config{BaseURL: server.URL, APIKey: "test-key"}
+	config.Use(record("outer"), record("inner"))
+	config.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, op *Operation) (*types.GraphQLResponse, error) {
+			op.Header.Set("X-Request-ID", "req-1")
+			return next(ctx, op)
+		}
+	})
+	client := NewClient(config)
+
+	if _, err := client.Debug(context.Background(), "stage"); err != nil {
+		t.Fatalf("Debug failed: %v", err)
+	}
+
+	if len(order) != 2 || order[0] != "outer:Debug" || order[1] != "inner:Debug" {
+		t.Errorf("Unexpected interceptor order: %v", order)
+	}
+	if gotHeader != "req-1" {
+		t.Errorf("Expected header set by interceptor, got %q", gotHeader)
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	injected := errors.New("injected failure")
+	var seen []string
+
+	config := Config{BaseURL: "http://127.0.0.1:0", APIKey: "test-key"}
+	config.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, op *Operation) (*types.GraphQLResponse, error) {
+			seen = append(seen, op.Type+" "+op.Name)
+			return nil, injected
+		}
+	})
+	client := NewClient(config)
+	ctx := context.Background()
+
+	if _, err := client.GenerateTenantToken(ctx, "token", "tenant"); !errors.Is(err, injected) {
+		t.Errorf("Expected injected error from GenerateTenantToken, got %v", err)
+	}
+	if err := client.DeleteResource(ctx, "task", "1"); !errors.Is(err, injected) {
+		t.Errorf("Expected injected error from DeleteResource, got %v", err)
+	}
+
+	expected := []string{"mutation GenerateTenantToken", "mutation DeleteData"}
+	if len(seen) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, seen)
+	}
+	for i := range expected {
+		if seen[i] != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], seen[i])
+		}
+	}
+}
+
+func TestInterceptorSeesResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"getModelData":{"results":[],"count":7}}}`))
+	}))
+	defer server.Close()
+
+	var variables map[string]interface{}
+	var response *types.GraphQLResponse
+
+	config := Config{BaseURL: server.URL, APIKey: "test-key"}
+	config.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, op *Operation) (*types.GraphQLResponse, error) {
+			variables = op.Variables
+			resp, err := next(ctx, op)
+			response = resp
+			return resp, err
+		}
+	})
+	client := NewClient(config)
+
+	result, err := client.SearchResources(context.Background(), "task", map[string]interface{}{"limit": 5}, false)
+	if err != nil {
+		t.Fatalf("SearchResources failed: %v", err)
+	}
+	if result.Count != 7 {
+		t.Errorf("Expected count 7, got %d", result.Count)
+	}
+	if variables["model"] != "task" || variables["limit"] != 5 {
+		t.Errorf("Unexpected variables seen by interceptor: %v", variables)
+	}
+	if response == nil || response.Data == nil {
+		t.Error("Expected interceptor to see the decoded response")
+	}
+}
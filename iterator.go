@@ -0,0 +1,194 @@
+package goapitosdk
+
+import (
+	"context"
+	"iter"
+
+	"github.com/apito-io/types"
+)
+
+// IterateOptions configures paginated iteration over documents
+type IterateOptions struct {
+	Filter    map[string]interface{} // Filter passed to every page request (_key, where, search); page and limit are managed by the iterator
+	PageSize  int                    // Documents requested per page (default: 100)
+	StartPage int                    // First page to request (default: 1)
+	Prefetch  bool                   // Fetch the next page in the background while the current one is consumed
+}
+
+// pageFetcher fetches one page of documents and the total count reported by the server
+type pageFetcher[D any] func(ctx context.Context, page, limit int) ([]*D, int, error)
+
+// IterateResources returns an iterator over every document of a model matching the filter.
+// Pages are requested until an empty page is returned or the documents received reach the
+// count reported by the server, so a server returning fewer documents per page than PageSize
+// is still scanned to the end. Documents of the previous page are skipped if they show up
+// again because the collection shifted mid-scan. Iteration stops at the first error, which is
+// yielded with a nil document.
+func (c *Client) IterateResources(ctx context.Context, model string, opts IterateOptions) iter.Seq2[*types.DefaultDocumentStructure, error] {
+	return iteratePages(ctx, opts, rawDocumentID, func(ctx context.Context, page, limit int) ([]*types.DefaultDocumentStructure, int, error) {
+		result, err := c.SearchResources(ctx, model, pageFilter(opts.Filter, page, limit), false)
+		if err != nil {
+			return nil, 0, err
+		}
+		return result.Results, result.Count, nil
+	})
+}
+
+// IterateRelationDocuments returns an iterator over every document related to _id through the connection.
+// Any "filter" entry of the connection is merged with opts.Filter, opts.Filter taking precedence.
+func (c *Client) IterateRelationDocuments(ctx context.Context, _id string, connection map[string]interface{}, opts IterateOptions) iter.Seq2[*types.DefaultDocumentStructure, error] {
+	return iteratePages(ctx, opts, rawDocumentID, func(ctx context.Context, page, limit int) ([]*types.DefaultDocumentStructure, int, error) {
+		result, err := c.GetRelationDocuments(ctx, _id, pageConnection(connection, opts.Filter, page, limit))
+		if err != nil {
+			return nil, 0, err
+		}
+		return result.Results, result.Count, nil
+	})
+}
+
+// IterateResourcesTyped returns an iterator over every document of a model with typed data
+func IterateResourcesTyped[T any](c *Client, ctx context.Context, model string, opts IterateOptions) iter.Seq2[*types.TypedDocumentStructure[T], error] {
+	return iteratePages(ctx, opts, typedDocumentID[T], func(ctx context.Context, page, limit int) ([]*types.TypedDocumentStructure[T], int, error) {
+		result, err := SearchResourcesTyped[T](c, ctx, model, pageFilter(opts.Filter, page, limit), false)
+		if err != nil {
+			return nil, 0, err
+		}
+		return result.Results, result.Count, nil
+	})
+}
+
+// IterateRelationDocumentsTyped returns an iterator over every related document with typed data
+func IterateRelationDocumentsTyped[T any](c *Client, ctx context.Context, _id string, connection map[string]interface{}, opts IterateOptions) iter.Seq2[*types.TypedDocumentStructure[T], error] {
+	return iteratePages(ctx, opts, typedDocumentID[T], func(ctx context.Context, page, limit int) ([]*types.TypedDocumentStructure[T], int, error) {
+		result, err := GetRelationDocumentsTyped[T](c, ctx, _id, pageConnection(connection, opts.Filter, page, limit))
+		if err != nil {
+			return nil, 0, err
+		}
+		return result.Results, result.Count, nil
+	})
+}
+
+func rawDocumentID(doc *types.DefaultDocumentStructure) string {
+	return doc.ID
+}
+
+func typedDocumentID[T any](doc *types.TypedDocumentStructure[T]) string {
+	return doc.ID
+}
+
+// pageFilter returns a copy of filter requesting the given page
+func pageFilter(filter map[string]interface{}, page, limit int) map[string]interface{} {
+	paged := make(map[string]interface{}, len(filter)+2)
+	for k, v := range filter {
+		paged[k] = v
+	}
+	paged["page"] = page
+	paged["limit"] = limit
+	return paged
+}
+
+// pageConnection returns a copy of connection whose filter, merged with the given one, requests the given page
+func pageConnection(connection, filter map[string]interface{}, page, limit int) map[string]interface{} {
+	merged := make(map[string]interface{})
+	if existing, ok := connection["filter"].(map[string]interface{}); ok {
+		for k, v := range existing {
+			merged[k] = v
+		}
+	}
+	for k, v := range filter {
+		merged[k] = v
+	}
+
+	conn := make(map[string]interface{}, len(connection)+1)
+	for k, v := range connection {
+		conn[k] = v
+	}
+	conn["filter"] = pageFilter(merged, page, limit)
+	return conn
+}
+
+// iteratePages drives a pageFetcher until the last page, yielding every document.
+// docID returns the ID used to skip documents repeated from the previous page.
+func iteratePages[D any](ctx context.Context, opts IterateOptions, docID func(*D) string, fetch pageFetcher[D]) iter.Seq2[*D, error] {
+	limit := opts.PageSize
+	if limit <= 0 {
+		limit = 100
+	}
+	page := opts.StartPage
+	if page <= 0 {
+		page = 1
+	}
+
+	type pageResult struct {
+		docs  []*D
+		count int
+		err   error
+	}
+
+	return func(yield func(*D, error) bool) {
+		// Cancelled when the consumer stops early so that a prefetch in flight is abandoned
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		fetchAsync := func(page int) <-chan pageResult {
+			ch := make(chan pageResult, 1)
+			go func() {
+				docs, count, err := fetch(ctx, page, limit)
+				ch <- pageResult{docs: docs, count: count, err: err}
+			}()
+			return ch
+		}
+
+		// Only the IDs of the previous page are kept: a document inserted or removed before the
+		// current position shifts at most a page boundary, and memory stays bounded by a page
+		var previous map[string]struct{}
+		received := 0
+		docs, count, err := fetch(ctx, page, limit)
+		if err == nil && page > 1 {
+			// Documents of the skipped pages count towards the total, at the page size the server applies
+			received = (page - 1) * len(docs)
+		}
+		for {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			received += len(docs)
+			lastPage := len(docs) == 0 || count > 0 && received >= count
+			var next <-chan pageResult
+			if opts.Prefetch && !lastPage {
+				next = fetchAsync(page + 1)
+			}
+
+			current := make(map[string]struct{}, len(docs))
+			for _, doc := range docs {
+				if doc == nil {
+					continue
+				}
+				if id := docID(doc); id != "" {
+					if _, ok := previous[id]; ok {
+						continue
+					}
+					current[id] = struct{}{}
+				}
+				if !yield(doc, nil) {
+					return
+				}
+			}
+			previous = current
+
+			if lastPage {
+				return
+			}
+
+			page++
+			if next != nil {
+				r := <-next
+				docs, count, err = r.docs, r.count, r.err
+			} else {
+				docs, count, err = fetch(ctx, page, limit)
+			}
+		}
+	}
+}
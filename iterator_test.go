@@ -0,0 +1,222 @@
+package goapitosdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newPagingServer serves getModelData pages out of total generated documents
+func newPagingServer(t *testing.T, total int, requests *int32) *httptest.Server {
+	t.Helper()
+	return newClampingPagingServer(t, total, 0, requests)
+}
+
+// newClampingPagingServer is newPagingServer for a server that serves at most maxLimit
+// documents per page whatever the requested limit, when maxLimit is set
+func newClampingPagingServer(t *testing.T, total, maxLimit int, requests *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		var payload struct {
+			Variables struct {
+				Page       int                    `json:"page"`
+				Limit      int                    `json:"limit"`
+				Connection map[string]interface{} `json:"connection"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		limit := payload.Variables.Limit
+		if maxLimit > 0 {
+			limit = min(limit, maxLimit)
+		}
+		start := (payload.Variables.Page - 1) * limit
+		end := min(start+limit, total)
+		results := []map[string]interface{}{}
+		for i := start; i < end; i++ {
+			results = append(results, map[string]interface{}{
+				"id":   fmt.Sprintf("doc-%d", i),
+				"data": map[string]interface{}{"name": fmt.Sprintf("task %d", i)},
+			})
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"getModelData": map[string]interface{}{"results": results, "count": total},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestIterateResources(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		var requests int32
+		server := newPagingServer(t, 25, &requests)
+		client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+		count := 0
+		for doc, err := range client.IterateResources(context.Background(), "task", IterateOptions{PageSize: 10, Prefetch: prefetch}) {
+			if err != nil {
+				t.Fatalf("prefetch=%v: unexpected error: %v", prefetch, err)
+			}
+			if doc.ID != fmt.Sprintf("doc-%d", count) {
+				t.Errorf("prefetch=%v: expected doc-%d, got %s", prefetch, count, doc.ID)
+			}
+			count++
+		}
+
+		if count != 25 {
+			t.Errorf("prefetch=%v: expected 25 documents, got %d", prefetch, count)
+		}
+		if requests != 3 {
+			t.Errorf("prefetch=%v: expected 3 page requests, got %d", prefetch, requests)
+		}
+	}
+}
+
+func TestIterateResourcesClampedPageSize(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		var requests int32
+		server := newClampingPagingServer(t, 25, 4, &requests)
+		client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+		count := 0
+		for doc, err := range client.IterateResources(context.Background(), "task", IterateOptions{PageSize: 10, Prefetch: prefetch}) {
+			if err != nil {
+				t.Fatalf("prefetch=%v: unexpected error: %v", prefetch, err)
+			}
+			if doc.ID != fmt.Sprintf("doc-%d", count) {
+				t.Errorf("prefetch=%v: expected doc-%d, got %s", prefetch, count, doc.ID)
+			}
+			count++
+		}
+
+		if count != 25 {
+			t.Errorf("prefetch=%v: expected 25 documents, got %d", prefetch, count)
+		}
+		if requests != 7 {
+			t.Errorf("prefetch=%v: expected 7 page requests, got %d", prefetch, requests)
+		}
+	}
+}
+
+func TestIterateResourcesShiftedPage(t *testing.T) {
+	// A document inserted at the front after the first page pushes its last document onto the next one
+	pages := map[int][]string{1: {"a", "b", "c"}, 2: {"c", "d", "e"}, 3: {"f"}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Variables struct {
+				Page int `json:"page"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		results := []map[string]interface{}{}
+		for _, id := range pages[payload.Variables.Page] {
+			results = append(results, map[string]interface{}{"id": id})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"getModelData": map[string]interface{}{"results": results, "count": 7},
+			},
+		})
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	var ids []string
+	for doc, err := range client.IterateResources(context.Background(), "task", IterateOptions{PageSize: 3}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, doc.ID)
+	}
+	if got := strings.Join(ids, ","); got != "a,b,c,d,e,f" {
+		t.Errorf("Expected each document once, got %s", got)
+	}
+}
+
+func TestIterateResourcesEarlyStop(t *testing.T) {
+	var requests int32
+	server := newPagingServer(t, 100, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	count := 0
+	for _, err := range client.IterateResources(context.Background(), "task", IterateOptions{PageSize: 10}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		count++
+		if count == 15 {
+			break
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 page requests after early stop, got %d", requests)
+	}
+}
+
+func TestIterateResourcesError(t *testing.T) {
+	server := newStaticServer(t, http.StatusInternalServerError, "boom")
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	var gotErr error
+	for doc, err := range client.IterateResources(context.Background(), "task", IterateOptions{}) {
+		if doc != nil {
+			t.Error("Expected nil document alongside error")
+		}
+		gotErr = err
+	}
+	if gotErr == nil {
+		t.Error("Expected error to be yielded")
+	}
+}
+
+func TestIterateTyped(t *testing.T) {
+	var requests int32
+	server := newPagingServer(t, 12, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	type named struct {
+		Name string `json:"name"`
+	}
+
+	count := 0
+	for doc, err := range IterateResourcesTyped[named](client, context.Background(), "task", IterateOptions{PageSize: 5}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if doc.Data.Name != fmt.Sprintf("task %d", count) {
+			t.Errorf("Unexpected name %q", doc.Data.Name)
+		}
+		count++
+	}
+	if count != 12 {
+		t.Errorf("Expected 12 documents, got %d", count)
+	}
+
+	count = 0
+	connection := map[string]interface{}{"model": "task", "to_model": "user"}
+	for _, err := range IterateRelationDocumentsTyped[named](client, context.Background(), "user-1", connection, IterateOptions{PageSize: 5}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		count++
+	}
+	if count != 12 {
+		t.Errorf("Expected 12 related documents, got %d", count)
+	}
+}
@@ -0,0 +1,161 @@
+package goapitosdk
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// redacted replaces sensitive values in log records
+const redacted = "[REDACTED]"
+
+// LogOptions configures round-trip logging
+type LogOptions struct {
+	Level        slog.Leveler // Level of successful round-trips (default: slog.LevelInfo)
+	ErrorLevel   slog.Leveler // Level of failed round-trips (default: slog.LevelWarn)
+	LogVariables bool         // Add the variables and request headers to records when the logger is enabled for debug
+	RedactFields []string     // Extra variable fields and headers to redact, matched case-insensitively at any depth
+}
+
+// defaultRedactedFields are always redacted from logged variables: the token passed to
+// GenerateTenantToken and common secret names
+var defaultRedactedFields = []string{"token", "password", "secret", "api_key", "apiKey"}
+
+// defaultRedactedHeaders are always redacted from logged headers
+var defaultRedactedHeaders = []string{"X-Apito-Key", "Authorization", "Cookie", "Proxy-Authorization"}
+
+// roundTripLogger logs GraphQL round-trips with secrets redacted
+type roundTripLogger struct {
+	logger     *slog.Logger
+	level      slog.Leveler
+	errorLevel slog.Leveler
+	variables  bool
+	redact     map[string]struct{} // Lower-cased field and header names
+}
+
+// newRoundTripLogger returns nil when no logger is configured
+func newRoundTripLogger(logger *slog.Logger, opts LogOptions) *roundTripLogger {
+	if logger == nil {
+		return nil
+	}
+
+	l := &roundTripLogger{
+		logger:     logger,
+		level:      opts.Level,
+		errorLevel: opts.ErrorLevel,
+		variables:  opts.LogVariables,
+		redact:     make(map[string]struct{}),
+	}
+	if l.level == nil {
+		l.level = slog.LevelInfo
+	}
+	if l.errorLevel == nil {
+		l.errorLevel = slog.LevelWarn
+	}
+	for _, names := range [][]string{defaultRedactedFields, defaultRedactedHeaders, opts.RedactFields} {
+		for _, name := range names {
+			l.redact[strings.ToLower(name)] = struct{}{}
+		}
+	}
+	return l
+}
+
+// log records a single round-trip
+func (l *roundTripLogger) log(ctx context.Context, op *Operation, header http.Header, start time.Time, requestSize, statusCode, responseSize int, err error) {
+	if l == nil {
+		return
+	}
+
+	level := l.level.Level()
+	if err != nil {
+		level = l.errorLevel.Level()
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", op.Name),
+		slog.String("type", op.Type),
+		slog.Duration("duration", time.Since(start)),
+		slog.Int("request_bytes", requestSize),
+	}
+	if model, ok := op.Variables["model"].(string); ok && model != "" {
+		attrs = append(attrs, slog.String("model", model))
+	}
+	if op.TenantID != "" {
+		attrs = append(attrs, slog.String("tenant_id", op.TenantID))
+	}
+	if statusCode != 0 {
+		attrs = append(attrs, slog.Int("status", statusCode), slog.Int("response_bytes", responseSize))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if l.variables && l.logger.Enabled(ctx, slog.LevelDebug) {
+		attrs = append(attrs,
+			slog.Any("variables", l.redactValue(op.Variables)),
+			slog.Any("headers", l.redactHeader(header)),
+		)
+	}
+
+	message := "apito round-trip"
+	if err != nil {
+		message = "apito round-trip failed"
+	}
+	l.logger.LogAttrs(ctx, level, message, attrs...)
+}
+
+// redactValue returns a JSON-shaped copy of v with sensitive fields replaced
+func (l *roundTripLogger) redactValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	// Normalise structs and typed values such as where expressions to maps and slices
+	data, err := json.Marshal(v)
+	if err != nil {
+		return redacted
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return redacted
+	}
+	return l.redactGeneric(generic)
+}
+
+func (l *roundTripLogger) redactGeneric(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if _, ok := l.redact[strings.ToLower(key)]; ok {
+				value[key] = redacted
+			} else {
+				value[key] = l.redactGeneric(item)
+			}
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = l.redactGeneric(item)
+		}
+		return value
+	}
+	return v
+}
+
+// redactHeader returns a copy of the header with sensitive values replaced
+func (l *roundTripLogger) redactHeader(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for key, values := range header {
+		if _, ok := l.redact[strings.ToLower(key)]; ok {
+			result[key] = redacted
+		} else {
+			result[key] = strings.Join(values, ", ")
+		}
+	}
+	return result
+}
@@ -0,0 +1,139 @@
+package goapitosdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/apito-io/types"
+)
+
+// logRecords decodes the JSON records written to buf
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLoggingRoundTrip(t *testing.T) {
+	server := newStaticServer(t, http.StatusOK, `{"data":{"getModelData":{"results":[],"count":0}}}`)
+
+	var buf bytes.Buffer
+	client := NewClient(Config{
+		BaseURL: server.URL,
+		APIKey:  "secret-key",
+		Logger:  slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})),
+	})
+
+	if _, err := client.Search(WithTenant(context.Background(), "tenant-1"), "product", SearchOptions{Search: "widget"}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+	expected := map[string]interface{}{
+		"level":     "INFO",
+		"operation": "GetModelData",
+		"model":     "product",
+		"tenant_id": "tenant-1",
+		"status":    float64(200),
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, record[key])
+		}
+	}
+	if _, ok := record["variables"]; ok {
+		t.Error("Expected variables to be omitted at info level")
+	}
+	if strings.Contains(buf.String(), "secret-key") {
+		t.Errorf("API key leaked into logs: %s", buf.String())
+	}
+}
+
+func TestLoggingRedactsVariablesAndHeaders(t *testing.T) {
+	server := newStaticServer(t, http.StatusOK, `{"data":{"generateTenantToken":{"token":"tenant-token"}}}`)
+
+	var buf bytes.Buffer
+	client := NewClient(Config{
+		BaseURL: server.URL,
+		APIKey:  "secret-key",
+		Logger:  slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		LogOptions: LogOptions{
+			Level:        slog.LevelDebug,
+			LogVariables: true,
+			RedactFields: []string{"ssn"},
+		},
+	})
+
+	if _, err := client.GenerateTenantToken(context.Background(), "base-token", "tenant-1"); err != nil {
+		t.Fatalf("GenerateTenantToken failed: %v", err)
+	}
+	payload := map[string]interface{}{"name": "Jane", "profile": map[string]interface{}{"SSN": "123-45-6789"}}
+	// Only the logged variables matter here, not the decoded response
+	client.CreateNewResource(context.Background(), &types.CreateAndUpdateRequest{Model: "person", Payload: payload})
+
+	output := buf.String()
+	for _, secret := range []string{"secret-key", "base-token", "tenant-token", "123-45-6789"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("%q leaked into logs: %s", secret, output)
+		}
+	}
+
+	records := logRecords(t, &buf)
+	if records[0]["level"] != "DEBUG" {
+		t.Errorf("Expected debug level, got %v", records[0]["level"])
+	}
+	variables, _ := records[0]["variables"].(map[string]interface{})
+	if variables["token"] != redacted || variables["tenantId"] != "tenant-1" {
+		t.Errorf("Expected only the token to be redacted, got %v", variables)
+	}
+	headers, _ := records[0]["headers"].(map[string]interface{})
+	if headers["X-Apito-Key"] != redacted {
+		t.Errorf("Expected the API key header to be redacted, got %v", headers)
+	}
+	if !strings.Contains(output, "Jane") {
+		t.Errorf("Expected non-sensitive fields to be logged, got %s", output)
+	}
+}
+
+func TestLoggingErrorLevel(t *testing.T) {
+	server := newStaticServer(t, http.StatusServiceUnavailable, `unavailable`)
+
+	var buf bytes.Buffer
+	client := NewClient(Config{
+		BaseURL:    server.URL,
+		APIKey:     "secret-key",
+		Logger:     slog.New(slog.NewJSONHandler(&buf, nil)),
+		LogOptions: LogOptions{Level: slog.LevelDebug, ErrorLevel: slog.LevelError},
+	})
+
+	if _, err := client.Debug(context.Background(), "stage"); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 1 || records[0]["level"] != "ERROR" || records[0]["status"] != float64(503) {
+		t.Fatalf("Expected one error record with status 503, got %v", records)
+	}
+	if records[0]["msg"] != "apito round-trip failed" || records[0]["error"] == nil {
+		t.Errorf("Expected the failure to be described, got %v", records[0])
+	}
+}
@@ -0,0 +1,530 @@
+package goapitosdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apito-io/types"
+)
+
+// Patch media types, sent as the patch_type argument of server-side patches
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// ErrPatchTestFailed is returned when a "test" operation of a JSON Patch does not match the document
+var ErrPatchTestFailed = errors.New("patch test failed")
+
+// Patch is a partial update of document data: a MergePatch or a JSONPatch
+type Patch interface {
+	// Apply returns a patched copy of data, leaving data untouched
+	Apply(data map[string]interface{}) (map[string]interface{}, error)
+	// Type returns the media type of the patch
+	Type() string
+}
+
+// MergePatch is an RFC 7396 JSON Merge Patch of the document data: objects are merged
+// recursively, null removes a key and any other value replaces the existing one
+type MergePatch map[string]interface{}
+
+// Type implements Patch
+func (p MergePatch) Type() string {
+	return MergePatchType
+}
+
+// Apply implements Patch
+func (p MergePatch) Apply(data map[string]interface{}) (map[string]interface{}, error) {
+	patched, err := jsonObject(data)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := jsonObject(map[string]interface{}(p))
+	if err != nil {
+		return nil, err
+	}
+	return mergeObjects(patched, patch), nil
+}
+
+// mergeObjects applies a merge patch to target in place
+func mergeObjects(target, patch map[string]interface{}) map[string]interface{} {
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			target[key] = value
+			continue
+		}
+		existing, ok := target[key].(map[string]interface{})
+		if !ok {
+			existing = make(map[string]interface{})
+		}
+		target[key] = mergeObjects(existing, nested)
+	}
+	return target
+}
+
+// PatchOperation is an RFC 6902 JSON Patch operation. Paths are JSON Pointers into the
+// document data, such as "/address/city" or "/tags/0"; "-" appends to an array.
+type PatchOperation struct {
+	Op    string      `json:"op"` // "add", "remove", "replace" or "test"
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON writes value for every op but remove, a null value included
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	if op.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{op.Op, op.Path})
+	}
+	type operation PatchOperation
+	return json.Marshal(operation(op))
+}
+
+// JSONPatch is an RFC 6902 JSON Patch of the document data. Operations apply in order
+// and the patch fails as a whole if any of them fails, "test" included.
+type JSONPatch []PatchOperation
+
+// Type implements Patch
+func (p JSONPatch) Type() string {
+	return JSONPatchType
+}
+
+// Validate checks the operations without applying them
+func (p JSONPatch) Validate() error {
+	for i, op := range p {
+		switch op.Op {
+		case "add", "remove", "replace", "test":
+		default:
+			return newValidationError("patch operation %d: unsupported op %q (supported: add, remove, replace, test)", i, op.Op)
+		}
+		if _, err := parsePointer(op.Path); err != nil {
+			return newValidationError("patch operation %d: %v", i, err)
+		}
+		if op.Path == "" && op.Op == "remove" {
+			return newValidationError("patch operation %d: the document data cannot be removed", i)
+		}
+	}
+	return nil
+}
+
+// Apply implements Patch
+func (p JSONPatch) Apply(data map[string]interface{}) (map[string]interface{}, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	doc, err := jsonObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, op := range p {
+		value, err := jsonValue(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("patch operation %d: %w", i, err)
+		}
+		tokens, _ := parsePointer(op.Path)
+		if doc, err = applyOperation(doc, op.Op, tokens, value); err != nil {
+			return nil, fmt.Errorf("patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	patched, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, newValidationError("patched document data is not an object")
+	}
+	return patched, nil
+}
+
+// applyOperation applies one operation to doc and returns the new root
+func applyOperation(doc interface{}, op string, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		switch op {
+		case "test":
+			if !reflect.DeepEqual(doc, value) {
+				return nil, ErrPatchTestFailed
+			}
+			return doc, nil
+		default:
+			return value, nil
+		}
+	}
+
+	parent, err := resolvePointer(doc, tokens[:len(tokens)-1])
+	if err != nil {
+		return nil, err
+	}
+	key := tokens[len(tokens)-1]
+
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		current, exists := container[key]
+		switch op {
+		case "add":
+			container[key] = value
+		case "replace", "remove":
+			if !exists {
+				return nil, fmt.Errorf("member %q does not exist", key)
+			}
+			if op == "remove" {
+				delete(container, key)
+			} else {
+				container[key] = value
+			}
+		case "test":
+			if !exists || !reflect.DeepEqual(current, value) {
+				return nil, ErrPatchTestFailed
+			}
+		}
+		return doc, nil
+
+	case []interface{}:
+		index, err := arrayIndex(key, len(container), op == "add")
+		if err != nil {
+			return nil, err
+		}
+		var updated []interface{}
+		switch op {
+		case "add":
+			updated = append(container[:index:index], append([]interface{}{value}, container[index:]...)...)
+		case "remove":
+			updated = append(container[:index:index], container[index+1:]...)
+		case "replace":
+			container[index] = value
+			return doc, nil
+		case "test":
+			if !reflect.DeepEqual(container[index], value) {
+				return nil, ErrPatchTestFailed
+			}
+			return doc, nil
+		}
+		// Arrays change length, so the new array is stored in its parent
+		return applyOperation(doc, "replace", tokens[:len(tokens)-1], updated)
+	}
+
+	return nil, fmt.Errorf("cannot address %q inside a %T", key, parent)
+}
+
+// resolvePointer returns the value at the given reference tokens
+func resolvePointer(doc interface{}, tokens []string) (interface{}, error) {
+	current := doc
+	for _, token := range tokens {
+		switch container := current.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q does not exist", token)
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			current = container[index]
+		default:
+			return nil, fmt.Errorf("cannot address %q inside a %T", token, current)
+		}
+	}
+	return current, nil
+}
+
+// arrayIndex parses an array reference token; "-" and len are only valid when adding
+func arrayIndex(token string, length int, adding bool) (int, error) {
+	if adding && token == "-" {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if index > length || index == length && !adding {
+		return 0, fmt.Errorf("array index %d out of range", index)
+	}
+	return index, nil
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// escapePointerToken escapes a member name for use in a JSON Pointer
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// jsonValue returns the JSON form of v: maps, slices, strings, float64, bool or nil.
+// The result never shares memory with v.
+func jsonValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to decode value: %w", err)
+	}
+	return generic, nil
+}
+
+// jsonObject returns the JSON form of v, which must encode to an object. Nil yields an empty object.
+func jsonObject(v interface{}) (map[string]interface{}, error) {
+	generic, err := jsonValue(v)
+	if err != nil {
+		return nil, err
+	}
+	if generic == nil {
+		return make(map[string]interface{}), nil
+	}
+	object, ok := generic.(map[string]interface{})
+	if !ok {
+		return nil, newValidationError("document data must be a JSON object, got %T", generic)
+	}
+	return object, nil
+}
+
+// DiffMergePatch returns the merge patch turning from into to. Both must encode to JSON objects.
+// Keys set to null in to cannot be told apart from removed keys, as is inherent to merge patches.
+func DiffMergePatch[T any](from, to T) (MergePatch, error) {
+	a, err := jsonObject(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := jsonObject(to)
+	if err != nil {
+		return nil, err
+	}
+	return diffObjects(a, b), nil
+}
+
+func diffObjects(from, to map[string]interface{}) MergePatch {
+	patch := MergePatch{}
+	for key := range from {
+		if _, ok := to[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, value := range to {
+		old, existed := from[key]
+		if existed && reflect.DeepEqual(old, value) {
+			continue
+		}
+		oldObject, oldIsObject := old.(map[string]interface{})
+		newObject, newIsObject := value.(map[string]interface{})
+		if existed && oldIsObject && newIsObject {
+			patch[key] = map[string]interface{}(diffObjects(oldObject, newObject))
+		} else {
+			patch[key] = value
+		}
+	}
+	return patch
+}
+
+// DiffJSONPatch returns the JSON Patch turning from into to. Both must encode to JSON objects.
+// Objects are compared member by member; arrays that differ are replaced as a whole.
+// Operations are sorted by path so that the result is deterministic.
+func DiffJSONPatch[T any](from, to T) (JSONPatch, error) {
+	a, err := jsonObject(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := jsonObject(to)
+	if err != nil {
+		return nil, err
+	}
+	patch := JSONPatch{}
+	diffFields(nil, a, b, func(path []string, kind ChangeKind, old, value interface{}) {
+		var pointer strings.Builder
+		for _, token := range path {
+			pointer.WriteString("/" + escapePointerToken(token))
+		}
+		switch kind {
+		case FieldRemoved:
+			patch = append(patch, PatchOperation{Op: "remove", Path: pointer.String()})
+		case FieldAdded:
+			patch = append(patch, PatchOperation{Op: "add", Path: pointer.String(), Value: value})
+		default:
+			patch = append(patch, PatchOperation{Op: "replace", Path: pointer.String(), Value: value})
+		}
+	})
+	return patch, nil
+}
+
+// diffFields walks two JSON objects in sorted key order and calls visit with the member
+// path of each difference. Objects present on both sides are compared member by member,
+// any other value that differs is reported as a whole.
+func diffFields(path []string, from, to map[string]interface{}, visit func(path []string, kind ChangeKind, old, value interface{})) {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		memberPath := append(path[:len(path):len(path)], key)
+		old, existed := from[key]
+		value, exists := to[key]
+		switch {
+		case !exists:
+			visit(memberPath, FieldRemoved, old, nil)
+		case !existed:
+			visit(memberPath, FieldAdded, nil, value)
+		case reflect.DeepEqual(old, value):
+		default:
+			oldObject, oldIsObject := old.(map[string]interface{})
+			newObject, newIsObject := value.(map[string]interface{})
+			if oldIsObject && newIsObject {
+				diffFields(memberPath, oldObject, newObject, visit)
+			} else {
+				visit(memberPath, FieldChanged, old, value)
+			}
+		}
+	}
+}
+
+// PatchOptions configures a patch
+type PatchOptions struct {
+	// ServerPatch sends the patch to the server's patchModelData mutation, which applies it
+	// atomically. The server must support the mutation; it is not part of the documented
+	// Apito schema. Otherwise the patch is applied client-side:
+	// the document is read, patched and written back with its revision as precondition,
+	// starting over when another writer changed it in between. Keys the patch removes are
+	// written as null.
+	ServerPatch bool
+
+	// ExpectedRevision rejects the patch with a *ConflictError unless the document is still
+	// at this revision; the patch is then not retried (optional)
+	ExpectedRevision string
+
+	// ServerPrecondition checks revisions on the server when the patch is applied client-side,
+	// see UpdateOptions
+	ServerPrecondition bool
+}
+
+// PatchResource applies a MergePatch or JSONPatch to the data of a document and returns the updated document
+func (c *Client) PatchResource(ctx context.Context, model, _id string, patch Patch, opts PatchOptions) (*types.DefaultDocumentStructure, error) {
+	var document *types.DefaultDocumentStructure
+	if err := c.patchResource(ctx, model, _id, patch, opts, &document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// PatchResourceTyped applies a patch to the data of a document and returns the updated typed document
+func PatchResourceTyped[T any](c *Client, ctx context.Context, model, _id string, patch Patch, opts PatchOptions) (*types.TypedDocumentStructure[T], error) {
+	var document *typedDocument[T]
+	if err := c.patchResource(ctx, model, _id, patch, opts, &document); err != nil {
+		return nil, err
+	}
+	return document.typed(), nil
+}
+
+func (c *Client) patchResource(ctx context.Context, model, _id string, patch Patch, opts PatchOptions, target interface{}) error {
+	if model == "" {
+		return newValidationError("model is required")
+	}
+	if _id == "" {
+		return newValidationError("id is required")
+	}
+	if patch == nil {
+		return newValidationError("patch is required")
+	}
+	if jsonPatch, ok := patch.(JSONPatch); ok {
+		if err := jsonPatch.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if opts.ServerPatch {
+		return c.serverPatch(ctx, model, _id, patch, opts.ExpectedRevision, target)
+	}
+
+	rmw := readModifyWrite{
+		model:              model,
+		id:                 _id,
+		expectedRevision:   opts.ExpectedRevision,
+		serverPrecondition: opts.ServerPrecondition,
+		replace:            true,
+	}
+	return c.readModifyWrite(ctx, rmw, func(doc *types.DefaultDocumentStructure) error {
+		data, err := patch.Apply(doc.Data)
+		if err != nil {
+			return fmt.Errorf("failed to apply patch: %w", err)
+		}
+		doc.Data = data
+		return nil
+	}, target)
+}
+
+// serverPatch runs the patchModelData mutation, decoding the patched document into target
+func (c *Client) serverPatch(ctx context.Context, model, _id string, patch Patch, expectedRevision string, target interface{}) error {
+	preconditionDeclaration, preconditionArg := "", ""
+	variables := map[string]interface{}{
+		"model":      model,
+		"_id":        _id,
+		"patch":      patch,
+		"patch_type": patch.Type(),
+	}
+	if expectedRevision != "" {
+		preconditionDeclaration, preconditionArg = ", $expected_revision: String", ", expected_revision: $expected_revision"
+		variables["expected_revision"] = expectedRevision
+	}
+
+	query := fmt.Sprintf(`
+		mutation PatchModelData($model: String!, $_id: String!, $patch: JSON!, $patch_type: String!%s) {
+			patchModelData(model_name: $model, _id: $_id, patch: $patch, patch_type: $patch_type%s) {
+				id
+				type
+				data
+				meta {
+					created_at
+					updated_at
+					status
+					revision
+					revision_at
+				}
+			}
+		}
+	`, preconditionDeclaration, preconditionArg)
+
+	response, err := c.executeGraphQLInto(ctx, query, variables, map[string]interface{}{"patchModelData": target})
+	if errors.Is(err, ErrConflict) {
+		return &ConflictError{Model: model, ID: _id, ExpectedRevision: expectedRevision, Err: err}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to patch resource: %w", err)
+	}
+
+	return decodeResponseField(response, "patchModelData", target)
+}
@@ -0,0 +1,363 @@
+package goapitosdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/apito-io/go-internal-sdk/apitotest"
+)
+
+func TestMergePatchApply(t *testing.T) {
+	data := map[string]interface{}{
+		"title":   "Draft",
+		"tags":    []interface{}{"a", "b"},
+		"address": map[string]interface{}{"city": "Oslo", "zip": "0150"},
+	}
+	patch := MergePatch{
+		"title":   "Final",
+		"tags":    []interface{}{"c"},
+		"address": map[string]interface{}{"zip": nil, "country": "NO"},
+		"author":  map[string]interface{}{"name": "Kim"},
+	}
+
+	patched, err := patch.Apply(data)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	want := map[string]interface{}{
+		"title":   "Final",
+		"tags":    []interface{}{"c"},
+		"address": map[string]interface{}{"city": "Oslo", "country": "NO"},
+		"author":  map[string]interface{}{"name": "Kim"},
+	}
+	if !reflect.DeepEqual(patched, want) {
+		t.Errorf("Unexpected result:\n got %v\nwant %v", patched, want)
+	}
+	if data["address"].(map[string]interface{})["zip"] != "0150" {
+		t.Error("Expected the original data to be left untouched")
+	}
+}
+
+func TestJSONPatchApply(t *testing.T) {
+	data := map[string]interface{}{
+		"title": "Draft",
+		"tags":  []interface{}{"a", "b"},
+		"a/b":   map[string]interface{}{"~": 1},
+	}
+
+	patched, err := JSONPatch{
+		{Op: "test", Path: "/title", Value: "Draft"},
+		{Op: "replace", Path: "/title", Value: "Final"},
+		{Op: "add", Path: "/tags/1", Value: "x"},
+		{Op: "add", Path: "/tags/-", Value: "z"},
+		{Op: "remove", Path: "/tags/0"},
+		{Op: "remove", Path: "/a~1b/~0"},
+		{Op: "add", Path: "/meta", Value: map[string]int{"views": 3}},
+	}.Apply(data)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	want := map[string]interface{}{
+		"title": "Final",
+		"tags":  []interface{}{"x", "b", "z"},
+		"a/b":   map[string]interface{}{},
+		"meta":  map[string]interface{}{"views": float64(3)},
+	}
+	if !reflect.DeepEqual(patched, want) {
+		t.Errorf("Unexpected result:\n got %v\nwant %v", patched, want)
+	}
+
+	cases := map[string]struct {
+		patch JSONPatch
+		err   error
+	}{
+		"failed test":     {JSONPatch{{Op: "test", Path: "/title", Value: "Other"}}, ErrPatchTestFailed},
+		"unsupported op":  {JSONPatch{{Op: "move", Path: "/title"}}, ErrValidation},
+		"relative path":   {JSONPatch{{Op: "remove", Path: "title"}}, ErrValidation},
+		"remove root":     {JSONPatch{{Op: "remove", Path: ""}}, ErrValidation},
+		"missing member":  {JSONPatch{{Op: "replace", Path: "/missing", Value: 1}}, nil},
+		"index too large": {JSONPatch{{Op: "add", Path: "/tags/5", Value: 1}}, nil},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := c.patch.Apply(data)
+			if err == nil || (c.err != nil && !errors.Is(err, c.err)) {
+				t.Errorf("Expected error %v, got %v", c.err, err)
+			}
+		})
+	}
+}
+
+type patchArticle struct {
+	Title  string            `json:"title"`
+	Tags   []string          `json:"tags,omitempty"`
+	Author *patchAuthor      `json:"author,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type patchAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+func TestDiffPatches(t *testing.T) {
+	from := patchArticle{Title: "Draft", Tags: []string{"a"}, Author: &patchAuthor{Name: "Kim", Email: "kim@example.com"}, Labels: map[string]string{"x": "1"}}
+	to := patchArticle{Title: "Final", Tags: []string{"a", "b"}, Author: &patchAuthor{Name: "Kim"}}
+
+	merge, err := DiffMergePatch(from, to)
+	if err != nil {
+		t.Fatalf("DiffMergePatch failed: %v", err)
+	}
+	wantMerge := MergePatch{
+		"title":  "Final",
+		"tags":   []interface{}{"a", "b"},
+		"author": map[string]interface{}{"email": nil},
+		"labels": nil,
+	}
+	if !reflect.DeepEqual(merge, wantMerge) {
+		t.Errorf("Unexpected merge patch:\n got %v\nwant %v", merge, wantMerge)
+	}
+
+	jsonPatch, err := DiffJSONPatch(from, to)
+	if err != nil {
+		t.Fatalf("DiffJSONPatch failed: %v", err)
+	}
+	wantJSON := JSONPatch{
+		{Op: "remove", Path: "/author/email"},
+		{Op: "remove", Path: "/labels"},
+		{Op: "replace", Path: "/tags", Value: []interface{}{"a", "b"}},
+		{Op: "replace", Path: "/title", Value: "Final"},
+	}
+	if !reflect.DeepEqual(jsonPatch, wantJSON) {
+		t.Errorf("Unexpected JSON patch:\n got %v\nwant %v", jsonPatch, wantJSON)
+	}
+
+	// Applying either patch to from yields to
+	fromData, _ := jsonObject(from)
+	toData, _ := jsonObject(to)
+	for _, patch := range []Patch{merge, jsonPatch} {
+		patched, err := patch.Apply(fromData)
+		if err != nil {
+			t.Fatalf("%T.Apply failed: %v", patch, err)
+		}
+		if !reflect.DeepEqual(patched, toData) {
+			t.Errorf("%T: got %v, want %v", patch, patched, toData)
+		}
+	}
+
+	if _, err := DiffMergePatch([]string{"a"}, []string{"b"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error for non-object values, got %v", err)
+	}
+}
+
+func TestPatchResource(t *testing.T) {
+	server := apitotest.NewServer()
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	seeded := server.Seed("", "article", map[string]interface{}{
+		"title":  "Draft",
+		"author": map[string]interface{}{"name": "Kim", "email": "kim@example.com"},
+	})
+
+	doc, err := client.PatchResource(WithFields(ctx, "title"), "article", seeded.ID, MergePatch{
+		"author": map[string]interface{}{"email": nil},
+	}, PatchOptions{})
+	if err != nil {
+		t.Fatalf("PatchResource failed: %v", err)
+	}
+	if doc.Data["title"] != "Draft" || !reflect.DeepEqual(doc.Data["author"], map[string]interface{}{"name": "Kim"}) {
+		t.Errorf("Expected the nested key to be removed and the rest kept, got %v", doc.Data)
+	}
+
+	typed, err := PatchResourceTyped[patchArticle](client, ctx, "article", seeded.ID, JSONPatch{
+		{Op: "test", Path: "/title", Value: "Draft"},
+		{Op: "replace", Path: "/title", Value: "Final"},
+	}, PatchOptions{ExpectedRevision: DocumentRevision(doc.Meta)})
+	if err != nil {
+		t.Fatalf("PatchResourceTyped failed: %v", err)
+	}
+	if typed.Data.Title != "Final" || typed.Data.Author == nil || typed.Data.Author.Name != "Kim" {
+		t.Errorf("Unexpected typed document: %+v", typed.Data)
+	}
+
+	// A stale revision is a conflict and is not retried
+	before := len(server.Requests())
+	_, err = client.PatchResource(ctx, "article", seeded.ID, MergePatch{"title": "Stale"}, PatchOptions{ExpectedRevision: DocumentRevision(doc.Meta)})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.ActualRevision != DocumentRevision(typed.Meta) {
+		t.Errorf("Expected a ConflictError, got %v", err)
+	}
+	if sent := len(server.Requests()) - before; sent != 1 {
+		t.Errorf("Expected a single read, got %d requests", sent)
+	}
+
+	// A failed test operation leaves the document untouched
+	_, err = client.PatchResource(ctx, "article", seeded.ID, JSONPatch{{Op: "test", Path: "/title", Value: "Draft"}}, PatchOptions{})
+	if !errors.Is(err, ErrPatchTestFailed) {
+		t.Errorf("Expected ErrPatchTestFailed, got %v", err)
+	}
+	if current, _ := server.Document("", "article", seeded.ID); current.Data["title"] != "Final" {
+		t.Errorf("Expected the document to be unchanged, got %v", current.Data)
+	}
+
+	// Removed keys are written as null
+	if _, err := client.PatchResource(ctx, "article", seeded.ID, JSONPatch{{Op: "remove", Path: "/author"}}, PatchOptions{}); err != nil {
+		t.Fatalf("PatchResource failed: %v", err)
+	}
+	requests := server.Requests()
+	if payload := requests[len(requests)-1].Variables["payload"]; !reflect.DeepEqual(payload, map[string]interface{}{"title": "Final", "author": nil}) {
+		t.Errorf("Expected the removed key to be sent as null, got %v", payload)
+	}
+
+	if _, err := client.PatchResource(ctx, "article", seeded.ID, JSONPatch{{Op: "copy", Path: "/x"}}, PatchOptions{}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
+func TestPatchResourceServerSide(t *testing.T) {
+	var requests []capturedRequest
+	server := newCapturingServer(t, `{"data":{"patchModelData":{"id":"a1","data":{"title":"Final"},"meta":{"updated_at":"2024-01-02T00:00:00Z"}}}}`, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	doc, err := client.PatchResource(context.Background(), "article", "a1", MergePatch{"title": "Final"}, PatchOptions{ServerPatch: true})
+	if err != nil {
+		t.Fatalf("PatchResource failed: %v", err)
+	}
+	if doc.ID != "a1" || doc.Data["title"] != "Final" {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+
+	if _, err := client.PatchResource(context.Background(), "article", "a1", JSONPatch{{Op: "remove", Path: "/title"}},
+		PatchOptions{ServerPatch: true, ExpectedRevision: "rev-1"}); err != nil {
+		t.Fatalf("PatchResource failed: %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("Expected a single request per patch, got %d", len(requests))
+	}
+	merge, jsonPatch := requests[0], requests[1]
+	if merge.Variables["patch_type"] != MergePatchType || !reflect.DeepEqual(merge.Variables["patch"], map[string]interface{}{"title": "Final"}) {
+		t.Errorf("Unexpected merge patch variables: %v", merge.Variables)
+	}
+	if _, sent := merge.Variables["expected_revision"]; sent || strings.Contains(merge.Query, "expected_revision") {
+		t.Error("Expected no precondition without an expected revision")
+	}
+	if jsonPatch.Variables["patch_type"] != JSONPatchType || jsonPatch.Variables["expected_revision"] != "rev-1" || !strings.Contains(jsonPatch.Query, "expected_revision: $expected_revision") {
+		t.Errorf("Unexpected JSON patch request: %s %v", jsonPatch.Query, jsonPatch.Variables)
+	}
+}
+
+func TestPatchOperationNullValue(t *testing.T) {
+	patch := JSONPatch{
+		{Op: "replace", Path: "/title", Value: nil},
+		{Op: "remove", Path: "/tags"},
+	}
+	encoded, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if want := `[{"op":"replace","path":"/title","value":null},{"op":"remove","path":"/tags"}]`; string(encoded) != want {
+		t.Errorf("Unexpected encoding:\n got %s\nwant %s", encoded, want)
+	}
+
+	var decoded JSONPatch
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	patched, err := decoded.Apply(map[string]interface{}{"title": "Draft", "tags": []interface{}{"a"}})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if value, ok := patched["title"]; !ok || value != nil || len(patched) != 1 {
+		t.Errorf("Expected the title replaced by null, got %v", patched)
+	}
+
+	diff, err := DiffJSONPatch(map[string]interface{}{"title": "Draft"}, map[string]interface{}{"title": nil})
+	if err != nil {
+		t.Fatalf("DiffJSONPatch failed: %v", err)
+	}
+	if encoded, _ := json.Marshal(diff); string(encoded) != `[{"op":"replace","path":"/title","value":null}]` {
+		t.Errorf("Expected the null value to be kept, got %s", encoded)
+	}
+}
+
+func TestPatchResourceServerPatch(t *testing.T) {
+	// The server applies the patches: the responses are the documents it is expected to return
+	responses := []string{
+		`{"data":{"patchModelData":{"id":"a1","data":{"title":"Draft","tags":["a"],"author":{"name":"Kim"}},"meta":{"updated_at":"2024-01-02T00:00:00Z"}}}}`,
+		`{"data":{"patchModelData":{"id":"a1","data":{"title":null,"tags":["a","b"],"author":{"name":"Kim"}},"meta":{"updated_at":"2024-01-03T00:00:00Z"}}}}`,
+		`{"errors":[{"message":"document \"a1\" was modified since revision 2024-01-02T00:00:00Z","extensions":{"code":"CONFLICT"}}]}`,
+		`{"errors":[{"message":"patch operation 0 (test /title): patch test failed","extensions":{"code":"BAD_USER_INPUT"}}]}`,
+	}
+	var requests []capturedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req capturedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		requests = append(requests, req)
+		w.Write([]byte(responses[len(requests)-1]))
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	doc, err := client.PatchResource(ctx, "article", "a1", MergePatch{
+		"author": map[string]interface{}{"email": nil},
+	}, PatchOptions{ServerPatch: true})
+	if err != nil {
+		t.Fatalf("PatchResource failed: %v", err)
+	}
+	want := map[string]interface{}{"title": "Draft", "tags": []interface{}{"a"}, "author": map[string]interface{}{"name": "Kim"}}
+	if !reflect.DeepEqual(doc.Data, want) {
+		t.Errorf("Unexpected document:\n got %v\nwant %v", doc.Data, want)
+	}
+
+	typed, err := PatchResourceTyped[patchArticle](client, ctx, "article", "a1", JSONPatch{
+		{Op: "test", Path: "/title", Value: "Draft"},
+		{Op: "add", Path: "/tags/-", Value: "b"},
+		{Op: "replace", Path: "/title", Value: nil},
+	}, PatchOptions{ServerPatch: true, ExpectedRevision: DocumentRevision(doc.Meta)})
+	if err != nil {
+		t.Fatalf("PatchResourceTyped failed: %v", err)
+	}
+	wantTyped := patchArticle{Tags: []string{"a", "b"}, Author: &patchAuthor{Name: "Kim"}}
+	if !reflect.DeepEqual(typed.Data, wantTyped) || DocumentRevision(typed.Meta) != "2024-01-03T00:00:00Z" {
+		t.Errorf("Unexpected typed document: %+v", typed)
+	}
+
+	wantPatches := []interface{}{
+		map[string]interface{}{"author": map[string]interface{}{"email": nil}},
+		[]interface{}{
+			map[string]interface{}{"op": "test", "path": "/title", "value": "Draft"},
+			map[string]interface{}{"op": "add", "path": "/tags/-", "value": "b"},
+			map[string]interface{}{"op": "replace", "path": "/title", "value": nil},
+		},
+	}
+	for i, want := range wantPatches {
+		if !reflect.DeepEqual(requests[i].Variables["patch"], want) {
+			t.Errorf("Unexpected patch %d:\n got %v\nwant %v", i, requests[i].Variables["patch"], want)
+		}
+	}
+	if requests[1].Variables["expected_revision"] != "2024-01-02T00:00:00Z" {
+		t.Errorf("Expected the revision to be sent, got %v", requests[1].Variables)
+	}
+
+	// The server checks the precondition and the test operations
+	_, err = client.PatchResource(ctx, "article", "a1", MergePatch{"title": "Stale"}, PatchOptions{ServerPatch: true, ExpectedRevision: DocumentRevision(doc.Meta)})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.ExpectedRevision != DocumentRevision(doc.Meta) {
+		t.Errorf("Expected a ConflictError, got %v", err)
+	}
+	if _, err := client.PatchResource(ctx, "article", "a1", JSONPatch{{Op: "test", Path: "/title", Value: "Draft"}}, PatchOptions{ServerPatch: true}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected the failed test to be rejected, got %v", err)
+	}
+}
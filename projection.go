@@ -0,0 +1,125 @@
+package goapitosdk
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/apito-io/types"
+)
+
+// WithFields returns a context selecting which data fields read calls made with it return.
+// Fields are paths into the document data, nested fields separated by dots ("address.city").
+// The selection is sent to the server as the fields argument and also applied to the decoded
+// documents, so only the selected fields are populated even if the server returns more.
+func WithFields(ctx context.Context, fields ...string) context.Context {
+	return context.WithValue(ctx, fieldsKey, fields)
+}
+
+// WithFieldsOf returns a context selecting the data fields declared by the json tags of T
+func WithFieldsOf[T any](ctx context.Context) context.Context {
+	return WithFields(ctx, FieldsOf[T]()...)
+}
+
+// fieldsFromContext returns the data fields selected with WithFields, if any
+func fieldsFromContext(ctx context.Context) []string {
+	fields, _ := ctx.Value(fieldsKey).([]string)
+	return fields
+}
+
+// FieldsOf returns the top-level data field names declared by the json tags of T.
+// Fields tagged "-" are skipped and embedded structs without a tag are flattened.
+func FieldsOf[T any]() []string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return structFields(t)
+}
+
+func structFields(t reflect.Type) []string {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" && tag == "-" {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fields = append(fields, structFields(ft)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+// isProjected reports whether a read selects data fields, through fields or WithFields
+func isProjected(ctx context.Context, fields []string) bool {
+	return len(fields) > 0 || len(fieldsFromContext(ctx)) > 0
+}
+
+// fieldsArgument returns the variable declaration and argument to add to a query when fields are selected
+func fieldsArgument(fields []string) (declaration string, argument string) {
+	if len(fields) == 0 {
+		return "", ""
+	}
+	return ", $fields: [String]", ", fields: $fields"
+}
+
+// projectDocument keeps only the selected fields in the document data
+func projectDocument(doc *types.DefaultDocumentStructure, fields []string) {
+	if doc == nil || len(fields) == 0 || doc.Data == nil {
+		return
+	}
+	doc.Data = projectData(doc.Data, fields)
+}
+
+// projectData returns a copy of data holding only the given dotted paths
+func projectData(data map[string]interface{}, fields []string) map[string]interface{} {
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		copyPath(projected, data, strings.Split(field, "."))
+	}
+	return projected
+}
+
+// copyPath copies the value at path from src into dst, creating intermediate maps
+func copyPath(dst, src map[string]interface{}, path []string) {
+	value, ok := src[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		dst[path[0]] = value
+		return
+	}
+
+	nestedSrc, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	nestedDst, ok := dst[path[0]].(map[string]interface{})
+	if !ok {
+		nestedDst = make(map[string]interface{})
+		dst[path[0]] = nestedDst
+	}
+	copyPath(nestedDst, nestedSrc, path[1:])
+}
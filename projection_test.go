@@ -0,0 +1,100 @@
+package goapitosdk
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFieldsOf(t *testing.T) {
+	type Base struct {
+		CreatedBy string `json:"created_by"`
+	}
+	type item struct {
+		Base
+		Name     string `json:"name,omitempty"`
+		Price    float64
+		Secret   string `json:"-"`
+		internal string
+	}
+
+	got := FieldsOf[item]()
+	want := []string{"created_by", "name", "Price"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if got := FieldsOf[*Product](); len(got) != 6 || got[0] != "name" {
+		t.Errorf("Expected pointer types to be dereferenced, got %v", got)
+	}
+}
+
+func TestProjectData(t *testing.T) {
+	data := map[string]interface{}{
+		"name":    "Widget",
+		"price":   10,
+		"address": map[string]interface{}{"city": "Dhaka", "zip": "1200"},
+	}
+
+	got := projectData(data, []string{"name", "address.city", "missing"})
+	want := map[string]interface{}{
+		"name":    "Widget",
+		"address": map[string]interface{}{"city": "Dhaka"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestGetSingleResourceWithFields(t *testing.T) {
+	var requests []capturedRequest
+	server := newCapturingServer(t, `{"data":{"getSingleData":{"id":"1","data":{"name":"Widget","price":10,"description":"long text"}}}}`, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	ctx := WithFieldsOf[struct {
+		Name  string  `json:"name"`
+		Price float64 `json:"price"`
+	}](context.Background())
+
+	doc, err := GetSingleResourceTyped[Product](client, ctx, "product", "1", false)
+	if err != nil {
+		t.Fatalf("GetSingleResourceTyped failed: %v", err)
+	}
+	if doc.Data.Name != "Widget" || doc.Data.Price != 10 || doc.Data.Description != "" {
+		t.Errorf("Expected only projected fields to be decoded, got %+v", doc.Data)
+	}
+
+	req := requests[0]
+	if !strings.Contains(req.Query, "$fields: [String]") || !strings.Contains(req.Query, "fields: $fields") {
+		t.Errorf("Expected fields argument in query:\n%s", req.Query)
+	}
+	if fields, _ := req.Variables["fields"].([]interface{}); len(fields) != 2 {
+		t.Errorf("Expected 2 fields to be sent, got %v", req.Variables["fields"])
+	}
+}
+
+func TestSearchWithoutFieldsOmitsArgument(t *testing.T) {
+	var requests []capturedRequest
+	server := newCapturingServer(t, `{"data":{"getModelData":{"results":[{"id":"1","data":{"name":"a","extra":1}}],"count":1}}}`, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	result, err := client.Search(context.Background(), "product", SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if strings.Contains(requests[0].Query, "fields") {
+		t.Errorf("Expected no fields argument:\n%s", requests[0].Query)
+	}
+	if len(result.Results[0].Data) != 2 {
+		t.Errorf("Expected full data, got %v", result.Results[0].Data)
+	}
+
+	result, err = client.Search(context.Background(), "product", SearchOptions{Fields: []string{"name"}})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(result.Results[0].Data) != 1 {
+		t.Errorf("Expected projected data, got %v", result.Results[0].Data)
+	}
+}
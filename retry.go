@@ -0,0 +1,169 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy configures automatic retries of GraphQL round-trips
+type RetryPolicy struct {
+	MaxAttempts          int           // Total attempts including the first one (default: 3)
+	BaseBackoff          time.Duration // Delay before the first retry, doubled on every attempt (default: 200ms)
+	MaxBackoff           time.Duration // Upper bound for a single delay (default: 5 seconds)
+	Jitter               float64       // Fraction of the delay randomised, between 0 and 1 (default: 0)
+	RetryableStatusCodes []int         // HTTP status codes that trigger a retry (default: 429, 502, 503, 504)
+	RetryNetworkErrors   bool          // Retry when the HTTP request itself fails (connection reset, refused, ...)
+	RespectRetryAfter    bool          // Use the Retry-After response header as the delay when present, capped at MaxBackoff
+	RetryMutations       bool          // Also retry mutations; only queries are retried by default
+}
+
+// DefaultRetryPolicy returns a retry policy suitable for most deployments
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:          3,
+		BaseBackoff:          200 * time.Millisecond,
+		MaxBackoff:           5 * time.Second,
+		Jitter:               0.2,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		RetryNetworkErrors:   true,
+		RespectRetryAfter:    true,
+	}
+}
+
+// RetryError is returned when a retry policy is configured and the round-trip still failed.
+// It records how many attempts were made and wraps the error of the last attempt.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts)", e.Err, e.Attempts)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// withDefaults returns a copy of the policy with zero values replaced by defaults
+func (p RetryPolicy) withDefaults() *RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.BaseBackoff <= 0 {
+		p.BaseBackoff = 200 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 5 * time.Second
+	}
+	if p.Jitter < 0 {
+		p.Jitter = 0
+	} else if p.Jitter > 1 {
+		p.Jitter = 1
+	}
+	if p.RetryableStatusCodes == nil {
+		p.RetryableStatusCodes = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	}
+	return &p
+}
+
+// allows reports whether the given operation type may be retried
+func (p *RetryPolicy) allows(opType string) bool {
+	return opType != "mutation" || p.RetryMutations
+}
+
+// shouldRetry reports whether the failure of an attempt is retryable
+func (p *RetryPolicy) shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr *HTTPError
+	if errors.As(err, &statusErr) {
+		for _, code := range p.RetryableStatusCodes {
+			if code == statusErr.StatusCode {
+				return true
+			}
+		}
+		return false
+	}
+
+	var netErr *transportError
+	if errors.As(err, &netErr) {
+		return p.RetryNetworkErrors && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	return false
+}
+
+// delay computes how long to wait before the given retry (1 for the first retry)
+func (p *RetryPolicy) delay(retry int, err error) time.Duration {
+	if p.RespectRetryAfter {
+		var statusErr *HTTPError
+		if errors.As(err, &statusErr) {
+			// A misbehaving proxy must not park the client for an hour
+			if d, ok := parseRetryAfter(statusErr.Header.Get("Retry-After"), time.Now()); ok {
+				return min(d, p.MaxBackoff)
+			}
+		}
+	}
+
+	d := p.BaseBackoff << (retry - 1)
+	if d <= 0 || d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		spread := time.Duration(float64(d) * p.Jitter)
+		d = d - spread + time.Duration(rand.Int64N(int64(2*spread)+1))
+	}
+	return d
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first.
+// It returns false without waiting when the context deadline would pass before d elapses.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return false
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		d := at.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
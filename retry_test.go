@@ -0,0 +1,186 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newRetryTestClient(url string, policy *RetryPolicy) *Client {
+	return NewClient(Config{
+		BaseURL:     url,
+		APIKey:      "test-key",
+		RetryPolicy: policy,
+	})
+}
+
+func TestRetryOnRetryableStatus(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"data":{"getSingleData":{"id":"1"}}}`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, &RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond})
+
+	doc, err := client.GetSingleResource(context.Background(), "task", "1", false)
+	if err != nil {
+		t.Fatalf("Expected success after retries, got %v", err)
+	}
+	if doc.ID != "1" {
+		t.Errorf("Expected document ID 1, got %s", doc.ID)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestRetryExhaustedReportsAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, &RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond})
+
+	_, err := client.SearchResources(context.Background(), "task", nil, false)
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("Expected RetryError, got %v", err)
+	}
+	if retryErr.Attempts != 2 || calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d (server saw %d)", retryErr.Attempts, calls)
+	}
+}
+
+func TestRetrySkipsMutationsByDefault(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	policy := &RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond}
+	client := newRetryTestClient(server.URL, policy)
+
+	if err := client.DeleteResource(context.Background(), "task", "1"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("Expected mutation to be attempted once, got %d", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	policy.RetryMutations = true
+	client = newRetryTestClient(server.URL, policy)
+
+	if err := client.DeleteResource(context.Background(), "task", "1"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if calls != 3 {
+		t.Errorf("Expected mutation to be attempted 3 times when opted in, got %d", calls)
+	}
+}
+
+func TestRetryNonRetryableStatus(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, &RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond})
+
+	if _, err := client.SearchResources(context.Background(), "task", nil, false); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single attempt for 400, got %d", calls)
+	}
+}
+
+func TestRetryRespectsContextDeadline(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(server.URL, &RetryPolicy{MaxAttempts: 5, RespectRetryAfter: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.SearchResources(ctx, "task", nil, false)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected to give up before waiting past the deadline, took %v", elapsed)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 attempt, got %d", calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if d, ok := parseRetryAfter("3", now); !ok || d != 3*time.Second {
+		t.Errorf("Expected 3s, got %v (%v)", d, ok)
+	}
+	if d, ok := parseRetryAfter(now.Add(5*time.Second).Format(http.TimeFormat), now); !ok || d != 5*time.Second {
+		t.Errorf("Expected 5s, got %v (%v)", d, ok)
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("Expected invalid Retry-After to be rejected")
+	}
+}
+
+func TestRetryAfterCappedAtMaxBackoff(t *testing.T) {
+	policy := (&RetryPolicy{RespectRetryAfter: true, MaxBackoff: 2 * time.Second}).withDefaults()
+
+	for _, value := range []string{"3600", time.Now().Add(24 * time.Hour).UTC().Format(http.TimeFormat)} {
+		err := &HTTPError{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": []string{value}}}
+		if d := policy.delay(1, err); d != 2*time.Second {
+			t.Errorf("Retry-After %q: expected the delay capped at 2s, got %v", value, d)
+		}
+	}
+
+	err := &HTTPError{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"1"}}}
+	if d := policy.delay(1, err); d != time.Second {
+		t.Errorf("Expected a short Retry-After to be honoured, got %v", d)
+	}
+}
+
+func TestParseOperation(t *testing.T) {
+	cases := []struct {
+		query, opType, opName string
+	}{
+		{"\n\t\tquery GetModelData($model: String!) { x }", "query", "GetModelData"},
+		{"mutation Debug($stage: String!) { x }", "mutation", "Debug"},
+		{"{ x }", "query", ""},
+		{"query { x }", "query", ""},
+	}
+	for _, c := range cases {
+		opType, opName := parseOperation(c.query)
+		if opType != c.opType || opName != c.opName {
+			t.Errorf("parseOperation(%q) = (%q, %q), want (%q, %q)", c.query, opType, opName, c.opType, c.opName)
+		}
+	}
+}
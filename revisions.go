@@ -0,0 +1,228 @@
+package goapitosdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apito-io/types"
+)
+
+// revisionFields selects the fields of a revision. The ID of a revision is its own,
+// meta.root_revision_id the ID of the document it belongs to and meta.updated_at the
+// revision the document had once written, as returned by DocumentRevision.
+const revisionFields = `
+				id
+				type
+				data
+				meta {
+					created_at
+					updated_at
+					status
+					revision
+					revision_at
+					root_revision_id
+				}`
+
+// ListRevisions returns the saved revisions of a document, oldest first.
+// The revision calls require Config.ServerExtensions.
+func (c *Client) ListRevisions(ctx context.Context, model, _id string) ([]*types.DefaultDocumentStructure, error) {
+	var revisions []*types.DefaultDocumentStructure
+	if err := c.listRevisions(ctx, model, _id, &revisions); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// ListRevisionsTyped returns the saved revisions of a document with typed data, oldest first
+func ListRevisionsTyped[T any](c *Client, ctx context.Context, model, _id string) ([]*types.TypedDocumentStructure[T], error) {
+	var revisions []*typedDocument[T]
+	if err := c.listRevisions(ctx, model, _id, &revisions); err != nil {
+		return nil, err
+	}
+
+	result := make([]*types.TypedDocumentStructure[T], len(revisions))
+	for i, revision := range revisions {
+		if revision != nil {
+			result[i] = revision.typed()
+		}
+	}
+	return result, nil
+}
+
+// listRevisions runs the listModelDataRevisions query, decoding the revisions into target
+func (c *Client) listRevisions(ctx context.Context, model, _id string, target interface{}) error {
+	if err := c.requireServerExtensions("ListRevisions"); err != nil {
+		return err
+	}
+	if model == "" {
+		return newValidationError("model is required")
+	}
+	if _id == "" {
+		return newValidationError("id is required")
+	}
+
+	query := `
+		query ListModelDataRevisions($model: String!, $_id: String!) {
+			listModelDataRevisions(model_name: $model, _id: $_id) {` + revisionFields + `
+			}
+		}
+	`
+	variables := map[string]interface{}{
+		"model": model,
+		"_id":   _id,
+	}
+
+	response, err := c.executeGraphQLInto(ctx, query, variables, map[string]interface{}{"listModelDataRevisions": target})
+	if err != nil {
+		return fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	return decodeResponseField(response, "listModelDataRevisions", target)
+}
+
+// GetRevision returns one revision of a document by its revision ID
+func (c *Client) GetRevision(ctx context.Context, model, _id, revisionID string) (*types.DefaultDocumentStructure, error) {
+	var revision *types.DefaultDocumentStructure
+	if err := c.getRevision(ctx, model, _id, revisionID, &revision); err != nil {
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// GetRevisionTyped returns one revision of a document with typed data
+func GetRevisionTyped[T any](c *Client, ctx context.Context, model, _id, revisionID string) (*types.TypedDocumentStructure[T], error) {
+	var revision *typedDocument[T]
+	if err := c.getRevision(ctx, model, _id, revisionID, &revision); err != nil {
+		return nil, err
+	}
+	return revision.typed(), nil
+}
+
+// getRevision runs the getModelDataRevision query, decoding the revision into target
+func (c *Client) getRevision(ctx context.Context, model, _id, revisionID string, target interface{}) error {
+	if err := c.requireServerExtensions("GetRevision"); err != nil {
+		return err
+	}
+	if model == "" {
+		return newValidationError("model is required")
+	}
+	if _id == "" {
+		return newValidationError("id is required")
+	}
+	if revisionID == "" {
+		return newValidationError("revision id is required")
+	}
+
+	query := `
+		query GetModelDataRevision($model: String!, $_id: String!, $revision_id: String!) {
+			getModelDataRevision(model_name: $model, _id: $_id, revision_id: $revision_id) {` + revisionFields + `
+			}
+		}
+	`
+	variables := map[string]interface{}{
+		"model":       model,
+		"_id":         _id,
+		"revision_id": revisionID,
+	}
+
+	response, err := c.executeGraphQLInto(ctx, query, variables, map[string]interface{}{"getModelDataRevision": target})
+	if err != nil {
+		return fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return decodeResponseField(response, "getModelDataRevision", target)
+}
+
+// RollbackToRevision restores the data of a document to one of its revisions and returns the
+// updated document. The rollback is a new write, so it is itself kept as a revision. It starts
+// over when the document changes while restored; the check is best-effort, as with RetryOnConflict.
+func (c *Client) RollbackToRevision(ctx context.Context, model, _id, revisionID string) (*types.DefaultDocumentStructure, error) {
+	var document *types.DefaultDocumentStructure
+	if err := c.rollbackToRevision(ctx, model, _id, revisionID, &document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// RollbackToRevisionTyped restores a document to one of its revisions and returns the updated typed document
+func RollbackToRevisionTyped[T any](c *Client, ctx context.Context, model, _id, revisionID string) (*types.TypedDocumentStructure[T], error) {
+	var document *typedDocument[T]
+	if err := c.rollbackToRevision(ctx, model, _id, revisionID, &document); err != nil {
+		return nil, err
+	}
+	return document.typed(), nil
+}
+
+func (c *Client) rollbackToRevision(ctx context.Context, model, _id, revisionID string, target interface{}) error {
+	revision, err := c.GetRevision(ctx, model, _id, revisionID)
+	if err != nil {
+		return err
+	}
+
+	rmw := readModifyWrite{model: model, id: _id, replace: true}
+	return c.readModifyWrite(ctx, rmw, func(doc *types.DefaultDocumentStructure) error {
+		data, err := jsonObject(revision.Data)
+		if err != nil {
+			return err
+		}
+		doc.Data = data
+		return nil
+	}, target)
+}
+
+// ChangeKind is the kind of a FieldChange
+type ChangeKind string
+
+// Field change kinds
+const (
+	FieldAdded   ChangeKind = "added"
+	FieldRemoved ChangeKind = "removed"
+	FieldChanged ChangeKind = "changed"
+)
+
+// FieldChange is a difference between the data of two revisions
+type FieldChange struct {
+	Path string      // Dotted path of the field, e.g. "address.city"
+	Kind ChangeKind  // FieldAdded, FieldRemoved or FieldChanged
+	From interface{} // Previous value in JSON form, nil when added
+	To   interface{} // New value in JSON form, nil when removed
+}
+
+// DiffRevisions compares the data of two revisions, or any two documents, field by field.
+// Nested objects are compared member by member; arrays that differ are reported as a whole.
+// Changes are sorted by path.
+func DiffRevisions(from, to *types.DefaultDocumentStructure) ([]FieldChange, error) {
+	if from == nil || to == nil {
+		return nil, newValidationError("both revisions are required")
+	}
+	return diffData(from.Data, to.Data)
+}
+
+// DiffRevisionsTyped compares the data of two typed revisions field by field, by their JSON form
+func DiffRevisionsTyped[T any](from, to *types.TypedDocumentStructure[T]) ([]FieldChange, error) {
+	if from == nil || to == nil {
+		return nil, newValidationError("both revisions are required")
+	}
+	return diffData(from.Data, to.Data)
+}
+
+func diffData(from, to interface{}) ([]FieldChange, error) {
+	a, err := jsonObject(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := jsonObject(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []FieldChange{}
+	diffFields(nil, a, b, func(path []string, kind ChangeKind, old, value interface{}) {
+		changes = append(changes, FieldChange{Path: strings.Join(path, "."), Kind: kind, From: old, To: value})
+	})
+	return changes, nil
+}
@@ -0,0 +1,136 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/apito-io/go-internal-sdk/apitotest"
+	"github.com/apito-io/types"
+)
+
+func TestRevisionHistoryAndRollback(t *testing.T) {
+	server := apitotest.NewServer()
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key", ServerExtensions: true})
+	ctx := context.Background()
+
+	seeded := server.Seed("", "article", map[string]interface{}{"title": "Draft", "tags": []interface{}{"a"}})
+	if _, err := client.UpdateResource(ctx, &types.CreateAndUpdateRequest{ID: seeded.ID, Model: "article", Payload: map[string]interface{}{"title": "Final", "author": map[string]interface{}{"name": "Kim"}}}); err != nil {
+		t.Fatalf("UpdateResource failed: %v", err)
+	}
+
+	revisions, err := client.ListRevisions(ctx, "article", seeded.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions failed: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Data["title"] != "Draft" || revisions[1].Data["title"] != "Final" {
+		t.Fatalf("Expected two revisions, oldest first, got %+v", revisions)
+	}
+	if revisions[0].Meta.RootRevisionID != seeded.ID || revisions[0].ID == seeded.ID {
+		t.Errorf("Unexpected revision meta: id %s, %+v", revisions[0].ID, revisions[0].Meta)
+	}
+
+	first, err := GetRevisionTyped[patchArticle](client, ctx, "article", seeded.ID, revisions[0].ID)
+	if err != nil {
+		t.Fatalf("GetRevisionTyped failed: %v", err)
+	}
+	if first.Data.Title != "Draft" || !reflect.DeepEqual(first.Data.Tags, []string{"a"}) {
+		t.Errorf("Unexpected revision: %+v", first.Data)
+	}
+
+	changes, err := DiffRevisions(revisions[0], revisions[1])
+	if err != nil {
+		t.Fatalf("DiffRevisions failed: %v", err)
+	}
+	want := []FieldChange{
+		{Path: "author", Kind: FieldAdded, To: map[string]interface{}{"name": "Kim"}},
+		{Path: "title", Kind: FieldChanged, From: "Draft", To: "Final"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Unexpected changes:\n got %+v\nwant %+v", changes, want)
+	}
+
+	doc, err := client.RollbackToRevision(ctx, "article", seeded.ID, revisions[0].ID)
+	if err != nil {
+		t.Fatalf("RollbackToRevision failed: %v", err)
+	}
+	// Keys added since the revision are cleared with null rather than relying on force_update
+	restored := map[string]interface{}{"author": nil}
+	for key, value := range revisions[0].Data {
+		restored[key] = value
+	}
+	if !reflect.DeepEqual(doc.Data, restored) {
+		t.Errorf("Expected the data of the first revision, got %v", doc.Data)
+	}
+	requests := server.Requests()
+	if write := requests[len(requests)-1]; write.OperationName != "UpdateModelData" || write.Variables["force_update"] != false || !reflect.DeepEqual(write.Variables["payload"], restored) {
+		t.Errorf("Unexpected rollback write: %s %v", write.OperationName, write.Variables)
+	}
+
+	// The rollback is kept as a revision of its own
+	history, err := ListRevisionsTyped[patchArticle](client, ctx, "article", seeded.ID)
+	if err != nil {
+		t.Fatalf("ListRevisionsTyped failed: %v", err)
+	}
+	if len(history) != 3 || history[2].Data.Title != "Draft" || DocumentRevision(history[2].Meta) != DocumentRevision(doc.Meta) {
+		t.Errorf("Expected the rollback at the end of the history, got %d revisions", len(history))
+	}
+
+	if _, err := client.GetRevision(ctx, "article", seeded.ID, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown revision, got %v", err)
+	}
+	if _, err := client.ListRevisions(ctx, "article", ""); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
+func TestDiffRevisionsTyped(t *testing.T) {
+	from := &types.TypedDocumentStructure[patchArticle]{Data: patchArticle{
+		Title:  "Draft",
+		Author: &patchAuthor{Name: "Kim", Email: "kim@example.com"},
+		Labels: map[string]string{"x": "1"},
+	}}
+	to := &types.TypedDocumentStructure[patchArticle]{Data: patchArticle{
+		Title:  "Draft",
+		Tags:   []string{"a"},
+		Author: &patchAuthor{Name: "Lee", Email: "kim@example.com"},
+	}}
+
+	changes, err := DiffRevisionsTyped(from, to)
+	if err != nil {
+		t.Fatalf("DiffRevisionsTyped failed: %v", err)
+	}
+	want := []FieldChange{
+		{Path: "author.name", Kind: FieldChanged, From: "Kim", To: "Lee"},
+		{Path: "labels", Kind: FieldRemoved, From: map[string]interface{}{"x": "1"}},
+		{Path: "tags", Kind: FieldAdded, To: []interface{}{"a"}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Unexpected changes:\n got %+v\nwant %+v", changes, want)
+	}
+
+	if changes, err := DiffRevisionsTyped(from, from); err != nil || len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v (%v)", changes, err)
+	}
+}
+
+func TestRevisionsRequireServerExtensions(t *testing.T) {
+	server := apitotest.NewServer()
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	article := server.Seed("", "article", map[string]interface{}{"title": "Draft"})
+
+	if _, err := client.ListRevisions(ctx, "article", article.ID); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported from ListRevisions, got %v", err)
+	}
+	if _, err := client.RollbackToRevision(ctx, "article", article.ID, "r1"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported from RollbackToRevision, got %v", err)
+	}
+	if requests := server.Requests(); len(requests) != 0 {
+		t.Errorf("Expected no request to be sent, got %d", len(requests))
+	}
+}
@@ -0,0 +1,307 @@
+package goapitosdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apito-io/types"
+)
+
+// SearchOptions describes a getModelData search. Zero values are left out of the request.
+type SearchOptions struct {
+	Page   int            // Page number, starting at 1
+	Limit  int            // Documents per page
+	Where  interface{}    // Where filter: a where.Expr or a map[string]interface{}
+	Search string         // Full-text search term
+	Key    interface{}    // _key filter
+	Sort   map[string]int // Sort direction per field: 1 ascending, -1 descending
+	Fields []string       // Data fields to return, dotted for nested fields; falls back to WithFields, all fields when empty
+	Status string         // Document status, e.g. StatusDraft or StatusPublished; requires Config.ServerExtensions
+	Locale string         // Locale of the returned data; falls back to WithLocale
+}
+
+// searchOptionKeys lists the keys accepted in the map-based filter of SearchResources
+var searchOptionKeys = []string{"_key", "page", "limit", "where", "search", "sort", "fields", "status", "locale"}
+
+// Validate checks the options for mistakes that the server would otherwise silently ignore
+func (o SearchOptions) Validate() error {
+	if o.Page < 0 {
+		return newValidationError("page must not be negative, got %d", o.Page)
+	}
+	if o.Limit < 0 {
+		return newValidationError("limit must not be negative, got %d", o.Limit)
+	}
+	for field, direction := range o.Sort {
+		if field == "" {
+			return newValidationError("sort field must not be empty")
+		}
+		if direction != 1 && direction != -1 {
+			return newValidationError("sort direction for %q must be 1 or -1, got %d", field, direction)
+		}
+	}
+	for _, field := range o.Fields {
+		if strings.TrimSpace(field) == "" {
+			return newValidationError("fields must not contain empty names")
+		}
+	}
+	return validateWhere(o.Where)
+}
+
+// searchArgument is an argument of the getModelData query
+type searchArgument struct {
+	name    string // Argument and variable name
+	gqlType string // GraphQL type of the variable
+}
+
+// Arguments always declared by GetModelData, followed by the ones only declared when set
+var (
+	baseSearchArguments = []searchArgument{
+		{"model", "String!"}, {"page", "Int"}, {"limit", "Int"}, {"_key", "JSON"}, {"where", "JSON"}, {"search", "String"},
+	}
+	optionalSearchArguments = []searchArgument{
+		{"sort", "JSON"}, {"fields", "[String]"}, {"status", "String"}, {"local", "String"},
+	}
+)
+
+// searchResultSelection is the selection set requested for getModelData results
+const searchResultSelection = `
+				results {
+					id
+					relation_doc_id
+					data
+					type
+					expire_at
+					meta {
+						created_at
+						updated_at
+						status
+						root_revision_id
+					}
+				}
+				count`
+
+// variables returns the GraphQL variables for a search on model
+func (o SearchOptions) variables(model string) map[string]interface{} {
+	variables := map[string]interface{}{
+		"model": model,
+	}
+	if o.Key != nil {
+		variables["_key"] = o.Key
+	}
+	if o.Page > 0 {
+		variables["page"] = o.Page
+	}
+	if o.Limit > 0 {
+		variables["limit"] = o.Limit
+	}
+	if o.Where != nil {
+		variables["where"] = o.Where
+	}
+	if o.Search != "" {
+		variables["search"] = o.Search
+	}
+	if len(o.Sort) > 0 {
+		variables["sort"] = o.Sort
+	}
+	if len(o.Fields) > 0 {
+		variables["fields"] = o.Fields
+	}
+	if o.Status != "" {
+		variables["status"] = o.Status
+	}
+	if o.Locale != "" {
+		variables["local"] = o.Locale
+	}
+	return variables
+}
+
+// buildSearchQuery builds the GetModelData query declaring the optional arguments present in variables
+func buildSearchQuery(variables map[string]interface{}, selection string) string {
+	args := append([]searchArgument{}, baseSearchArguments...)
+	for _, arg := range optionalSearchArguments {
+		if _, ok := variables[arg.name]; ok {
+			args = append(args, arg)
+		}
+	}
+
+	declarations := make([]string, len(args))
+	arguments := make([]string, len(args))
+	for i, arg := range args {
+		declarations[i] = fmt.Sprintf("$%s: %s", arg.name, arg.gqlType)
+		arguments[i] = fmt.Sprintf("%s: $%s", arg.name, arg.name)
+	}
+
+	return fmt.Sprintf(`
+		query GetModelData(%s) {
+			getModelData(%s) {%s
+			}
+		}
+	`, strings.Join(declarations, ", "), strings.Join(arguments, ", "), selection)
+}
+
+// Search searches for resources in the specified model using typed search options
+func (c *Client) Search(ctx context.Context, model string, opts SearchOptions) (*types.SearchResult, error) {
+	var searchResult *types.SearchResult
+	opts, err := c.search(ctx, model, opts, &searchResult)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range searchResult.Results {
+		projectDocument(doc, opts.Fields)
+	}
+
+	return searchResult, nil
+}
+
+// SearchTyped searches for resources using typed search options and returns typed results
+func SearchTyped[T any](c *Client, ctx context.Context, model string, opts SearchOptions) (*types.TypedSearchResult[T], error) {
+	if isProjected(ctx, opts.Fields) {
+		rawResults, err := c.Search(ctx, model, opts)
+		if err != nil {
+			return nil, err
+		}
+		return convertToTypedSearchResult[T](rawResults)
+	}
+
+	var searchResult *typedSearchResult[T]
+	if _, err := c.search(ctx, model, opts, &searchResult); err != nil {
+		return nil, err
+	}
+	return searchResult.typed(), nil
+}
+
+// search runs a getModelData search, decoding the result into target. It returns the
+// options completed from the context.
+func (c *Client) search(ctx context.Context, model string, opts SearchOptions, target interface{}) (SearchOptions, error) {
+	if err := c.validateSearch(model, opts); err != nil {
+		return opts, err
+	}
+	if len(opts.Fields) == 0 {
+		opts.Fields = fieldsFromContext(ctx)
+	}
+	if opts.Locale == "" {
+		opts.Locale = LocaleFromContext(ctx)
+	}
+
+	variables := opts.variables(model)
+	response, err := c.executeGraphQLInto(ctx, buildSearchQuery(variables, searchResultSelection), variables, map[string]interface{}{"getModelData": target})
+	if err != nil {
+		return opts, fmt.Errorf("failed to search resources: %w", err)
+	}
+
+	return opts, decodeResponseField(response, "getModelData", target)
+}
+
+// validateSearch checks the model and options of a search
+func (c *Client) validateSearch(model string, opts SearchOptions) error {
+	if model == "" {
+		return newValidationError("model is required")
+	}
+	if opts.Status != "" {
+		if err := c.requireServerExtensions("status filter"); err != nil {
+			return err
+		}
+	}
+	return opts.Validate()
+}
+
+// searchOptionsFromMap converts the map-based filter of SearchResources, rejecting unknown keys
+func searchOptionsFromMap(filter map[string]interface{}) (SearchOptions, error) {
+	var opts SearchOptions
+
+	keys := make([]string, 0, len(filter))
+	for key := range filter {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := filter[key]
+		if value == nil {
+			continue
+		}
+
+		var ok bool
+		switch key {
+		case "_key":
+			opts.Key, ok = value, true
+		case "page":
+			opts.Page, ok = toInt(value)
+		case "limit":
+			opts.Limit, ok = toInt(value)
+		case "where":
+			opts.Where, ok = value, true
+		case "search":
+			opts.Search, ok = value.(string)
+		case "status":
+			opts.Status, ok = value.(string)
+		case "locale":
+			opts.Locale, ok = value.(string)
+		case "fields":
+			opts.Fields, ok = toStringSlice(value)
+		case "sort":
+			opts.Sort, ok = toSortMap(value)
+		default:
+			return opts, newValidationError("unknown search option %q (supported: %s)", key, strings.Join(searchOptionKeys, ", "))
+		}
+		if !ok {
+			return opts, newValidationError("invalid value for search option %q: %v (%T)", key, value, value)
+		}
+	}
+
+	return opts, nil
+}
+
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), true
+		}
+	}
+	return 0, false
+}
+
+func toStringSlice(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		result := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			result[i] = s
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+func toSortMap(value interface{}) (map[string]int, bool) {
+	switch v := value.(type) {
+	case map[string]int:
+		return v, true
+	case map[string]interface{}:
+		result := make(map[string]int, len(v))
+		for field, direction := range v {
+			d, ok := toInt(direction)
+			if !ok {
+				return nil, false
+			}
+			result[field] = d
+		}
+		return result, true
+	}
+	return nil, false
+}
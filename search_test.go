@@ -0,0 +1,115 @@
+package goapitosdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apito-io/go-internal-sdk/where"
+)
+
+// capturedRequest is a GraphQL request body as received by a test server
+type capturedRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// newCapturingServer records every GraphQL request and answers with body
+func newCapturingServer(t *testing.T, body string, requests *[]capturedRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req capturedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		*requests = append(*requests, req)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSearchWithOptions(t *testing.T) {
+	var requests []capturedRequest
+	server := newCapturingServer(t, `{"data":{"getModelData":{"results":[{"id":"1","data":{"name":"Widget"}}],"count":1}}}`, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key", ServerExtensions: true})
+
+	opts := SearchOptions{
+		Page:   2,
+		Limit:  10,
+		Where:  where.Field("status").Eq("done"),
+		Sort:   map[string]int{"created_at": -1},
+		Status: "published",
+		Locale: "en",
+	}
+
+	results, err := SearchTyped[Product](client, context.Background(), "product", opts)
+	if err != nil {
+		t.Fatalf("SearchTyped failed: %v", err)
+	}
+	if results.Count != 1 || results.Results[0].Data.Name != "Widget" {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+
+	req := requests[0]
+	for _, fragment := range []string{"$sort: JSON", "$status: String", "$local: String", "sort: $sort"} {
+		if !strings.Contains(req.Query, fragment) {
+			t.Errorf("Expected query to contain %q:\n%s", fragment, req.Query)
+		}
+	}
+	if strings.Contains(req.Query, "$fields") {
+		t.Errorf("Expected unset fields argument to be omitted:\n%s", req.Query)
+	}
+	if req.Variables["page"] != float64(2) || req.Variables["status"] != "published" || req.Variables["local"] != "en" {
+		t.Errorf("Unexpected variables: %v", req.Variables)
+	}
+	if _, ok := req.Variables["search"]; ok {
+		t.Errorf("Expected empty search to be omitted: %v", req.Variables)
+	}
+}
+
+func TestSearchResourcesRejectsUnknownKeys(t *testing.T) {
+	var requests []capturedRequest
+	server := newCapturingServer(t, `{"data":{"getModelData":{"results":[],"count":0}}}`, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	_, err := client.SearchResources(ctx, "task", map[string]interface{}{"limt": 10}, false)
+	if !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), `"limt"`) {
+		t.Errorf("Expected validation error naming the key, got %v", err)
+	}
+
+	_, err = client.SearchResources(ctx, "task", map[string]interface{}{"page": "one"}, false)
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected validation error for bad page value, got %v", err)
+	}
+
+	_, err = client.SearchResources(ctx, "task", map[string]interface{}{"page": 1, "limit": float64(5), "sort": map[string]interface{}{"name": 1}}, false)
+	if err != nil {
+		t.Fatalf("Expected valid filter to succeed, got %v", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("Expected only the valid search to reach the server, got %d requests", len(requests))
+	}
+	if requests[0].Variables["limit"] != float64(5) {
+		t.Errorf("Unexpected variables: %v", requests[0].Variables)
+	}
+}
+
+func TestSearchOptionsValidate(t *testing.T) {
+	invalid := []SearchOptions{
+		{Page: -1},
+		{Limit: -5},
+		{Sort: map[string]int{"name": 2}},
+		{Fields: []string{"name", " "}},
+	}
+	for _, opts := range invalid {
+		if err := opts.Validate(); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected validation error for %+v, got %v", opts, err)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package goapitosdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apito-io/types"
+)
+
+// Publishing statuses of a document, reported in meta.status
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+)
+
+// ScheduledTransition is a status change the server applies at a given time
+type ScheduledTransition struct {
+	ID     string    `json:"id"`     // Document ID
+	Status string    `json:"status"` // Status the document will be set to
+	At     time.Time `json:"at"`     // Time of the change
+}
+
+// Publish sets the status of a document to StatusPublished and returns the updated document
+func (c *Client) Publish(ctx context.Context, model, _id string) (*types.DefaultDocumentStructure, error) {
+	return c.SetStatus(ctx, model, _id, StatusPublished)
+}
+
+// Unpublish sets the status of a document back to StatusDraft and returns the updated document
+func (c *Client) Unpublish(ctx context.Context, model, _id string) (*types.DefaultDocumentStructure, error) {
+	return c.SetStatus(ctx, model, _id, StatusDraft)
+}
+
+// SetStatus sets meta.status of a document and returns the updated document. Use Delete and
+// RestoreResource to move documents to and from the trash. It requires Config.ServerExtensions.
+func (c *Client) SetStatus(ctx context.Context, model, _id, status string) (*types.DefaultDocumentStructure, error) {
+	if err := c.requireServerExtensions("SetStatus"); err != nil {
+		return nil, err
+	}
+	if err := validateStatusChange(model, _id, status); err != nil {
+		return nil, err
+	}
+
+	query := `
+		mutation UpdateModelDataStatus($model: String!, $_id: String!, $status: String!) {
+			updateModelDataStatus(model_name: $model, _id: $_id, status: $status) ` + documentSelection + `
+		}
+	`
+	variables := map[string]interface{}{
+		"model":  model,
+		"_id":    _id,
+		"status": status,
+	}
+
+	var document *types.DefaultDocumentStructure
+	response, err := c.executeGraphQLInto(ctx, query, variables, map[string]interface{}{"updateModelDataStatus": &document})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update status: %w", err)
+	}
+	if err := decodeResponseField(response, "updateModelDataStatus", &document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// ScheduleTransition schedules a status change of a document, such as publishing it at a
+// given time. The document keeps its current status until then; a time in the past applies
+// the change right away. It requires Config.ServerExtensions.
+func (c *Client) ScheduleTransition(ctx context.Context, model, _id, status string, at time.Time) (*ScheduledTransition, error) {
+	if err := c.requireServerExtensions("ScheduleTransition"); err != nil {
+		return nil, err
+	}
+	if err := validateStatusChange(model, _id, status); err != nil {
+		return nil, err
+	}
+	if at.IsZero() {
+		return nil, newValidationError("transition time is required")
+	}
+
+	query := `
+		mutation ScheduleModelDataStatus($model: String!, $_id: String!, $status: String!, $at: String!) {
+			scheduleModelDataStatus(model_name: $model, _id: $_id, status: $status, at: $at) {
+				id
+				status
+				at
+			}
+		}
+	`
+	variables := map[string]interface{}{
+		"model":  model,
+		"_id":    _id,
+		"status": status,
+		"at":     at.UTC().Format(time.RFC3339Nano),
+	}
+
+	var transition *ScheduledTransition
+	response, err := c.executeGraphQLInto(ctx, query, variables, map[string]interface{}{"scheduleModelDataStatus": &transition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule status transition: %w", err)
+	}
+	if err := decodeResponseField(response, "scheduleModelDataStatus", &transition); err != nil {
+		return nil, err
+	}
+	if transition.ID == "" {
+		transition.ID = _id
+	}
+
+	return transition, nil
+}
+
+// Drafts searches the documents of a model that are in StatusDraft. It requires Config.ServerExtensions.
+func (c *Client) Drafts(ctx context.Context, model string, opts SearchOptions) (*types.SearchResult, error) {
+	opts.Status = StatusDraft
+	return c.Search(ctx, model, opts)
+}
+
+// Published searches the documents of a model that are in StatusPublished. It requires Config.ServerExtensions.
+func (c *Client) Published(ctx context.Context, model string, opts SearchOptions) (*types.SearchResult, error) {
+	opts.Status = StatusPublished
+	return c.Search(ctx, model, opts)
+}
+
+// BulkPublish publishes many documents of a model, packing several mutations per request.
+// The returned report always covers every ID; the error is a *BulkError when any item failed.
+func (c *Client) BulkPublish(ctx context.Context, model string, ids []string, opts BulkOptions) (*BulkResult, error) {
+	return c.BulkSetStatus(ctx, model, ids, StatusPublished, opts)
+}
+
+// BulkUnpublish sets many documents of a model back to StatusDraft, see BulkPublish
+func (c *Client) BulkUnpublish(ctx context.Context, model string, ids []string, opts BulkOptions) (*BulkResult, error) {
+	return c.BulkSetStatus(ctx, model, ids, StatusDraft, opts)
+}
+
+// BulkSetStatus sets the status of many documents of a model, packing several mutations per request.
+// The returned report always covers every ID; the error is a *BulkError when any item failed.
+// It requires Config.ServerExtensions.
+func (c *Client) BulkSetStatus(ctx context.Context, model string, ids []string, status string, opts BulkOptions) (*BulkResult, error) {
+	if err := c.requireServerExtensions("BulkSetStatus"); err != nil {
+		return nil, err
+	}
+
+	return c.runBulk(ctx, "BulkSetStatus", len(ids), opts, func(i int, alias string) (*bulkItem, error) {
+		if err := validateStatusChange(model, ids[i], status); err != nil {
+			return nil, err
+		}
+
+		return &bulkItem{
+			id: ids[i],
+			declarations: []string{
+				fmt.Sprintf("$model_%d: String!", i), fmt.Sprintf("$_id_%d: String!", i), fmt.Sprintf("$status_%d: String!", i),
+			},
+			call: fmt.Sprintf("%s: updateModelDataStatus(model_name: $model_%d, _id: $_id_%d, status: $status_%d) %s",
+				alias, i, i, i, documentSelection),
+			variables: map[string]interface{}{
+				fmt.Sprintf("model_%d", i):  model,
+				fmt.Sprintf("_id_%d", i):    ids[i],
+				fmt.Sprintf("status_%d", i): status,
+			},
+		}, nil
+	})
+}
+
+// validateStatusChange checks the arguments of a status change
+func validateStatusChange(model, _id, status string) error {
+	if model == "" {
+		return newValidationError("model is required")
+	}
+	if _id == "" {
+		return newValidationError("id is required")
+	}
+	if status == "" {
+		return newValidationError("status is required")
+	}
+	if status == StatusTrashed || status == StatusDeleted {
+		return newValidationError("status %q is set by Delete, use Delete and RestoreResource instead", status)
+	}
+	return nil
+}
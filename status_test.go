@@ -0,0 +1,171 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apito-io/go-internal-sdk/apitotest"
+)
+
+func TestPublishWorkflow(t *testing.T) {
+	server := apitotest.NewServer()
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key", ServerExtensions: true})
+	ctx := context.Background()
+
+	first := server.Seed("", "post", map[string]interface{}{"title": "First"})
+	second := server.Seed("", "post", map[string]interface{}{"title": "Second"})
+
+	doc, err := client.Publish(ctx, "post", first.ID)
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if doc.Meta == nil || doc.Meta.Status != StatusPublished || doc.Data["title"] != "First" {
+		t.Errorf("Unexpected published document: %+v", doc)
+	}
+
+	published, err := client.Published(ctx, "post", SearchOptions{})
+	if err != nil {
+		t.Fatalf("Published failed: %v", err)
+	}
+	drafts, err := client.Drafts(ctx, "post", SearchOptions{})
+	if err != nil {
+		t.Fatalf("Drafts failed: %v", err)
+	}
+	if published.Count != 1 || published.Results[0].ID != first.ID || drafts.Count != 1 || drafts.Results[0].ID != second.ID {
+		t.Errorf("Expected one published and one draft post, got %d published and %d drafts", published.Count, drafts.Count)
+	}
+
+	if doc, err = client.Unpublish(ctx, "post", first.ID); err != nil || doc.Meta.Status != StatusDraft {
+		t.Errorf("Expected the post back in draft, got %+v (%v)", doc, err)
+	}
+
+	if _, err := client.SetStatus(ctx, "post", first.ID, StatusTrashed); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error for the trash status, got %v", err)
+	}
+	if _, err := client.Publish(ctx, "post", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestScheduleTransition(t *testing.T) {
+	server := apitotest.NewServer()
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key", ServerExtensions: true})
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	server.SetClock(func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	})
+
+	post := server.Seed("", "post", map[string]interface{}{"title": "Launch"})
+	at := now.Add(time.Hour)
+
+	transition, err := client.ScheduleTransition(ctx, "post", post.ID, StatusPublished, at)
+	if err != nil {
+		t.Fatalf("ScheduleTransition failed: %v", err)
+	}
+	if transition.ID != post.ID || transition.Status != StatusPublished || !transition.At.Equal(at) {
+		t.Errorf("Unexpected transition: %+v", transition)
+	}
+	if _, err := client.ScheduleTransition(ctx, "post", post.ID, StatusDraft, at.Add(time.Hour)); err != nil {
+		t.Fatalf("ScheduleTransition failed: %v", err)
+	}
+
+	status := func() string {
+		doc, err := client.GetSingleResource(ctx, "post", post.ID, false)
+		if err != nil {
+			t.Fatalf("GetSingleResource failed: %v", err)
+		}
+		return doc.Meta.Status
+	}
+
+	if got := status(); got != StatusDraft {
+		t.Errorf("Expected the post to stay in draft until the transition, got %s", got)
+	}
+	mu.Lock()
+	now = at
+	mu.Unlock()
+	if got := status(); got != StatusPublished {
+		t.Errorf("Expected the post to be published at the transition time, got %s", got)
+	}
+	mu.Lock()
+	now = at.Add(2 * time.Hour)
+	mu.Unlock()
+	if got := status(); got != StatusDraft {
+		t.Errorf("Expected the second transition to unpublish the post, got %s", got)
+	}
+
+	if _, err := client.ScheduleTransition(ctx, "post", post.ID, StatusPublished, time.Time{}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error for a zero time, got %v", err)
+	}
+}
+
+func TestBulkSetStatus(t *testing.T) {
+	server := apitotest.NewServer()
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key", ServerExtensions: true})
+	ctx := context.Background()
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		ids = append(ids, server.Seed("", "post", map[string]interface{}{"n": i}).ID)
+	}
+
+	result, err := client.BulkPublish(ctx, "post", append(ids, "missing"), BulkOptions{ChunkSize: 2})
+	var bulkErr *BulkError
+	if !errors.As(err, &bulkErr) || bulkErr.Failed != 1 {
+		t.Fatalf("Expected one failed item, got %v", err)
+	}
+	if result.Succeeded != 5 || !errors.Is(result.Items[5].Err, ErrNotFound) {
+		t.Errorf("Unexpected report: %+v", result)
+	}
+	for _, item := range result.Items[:5] {
+		if item.Document == nil || item.Document.Meta.Status != StatusPublished {
+			t.Errorf("Expected item %d to be published, got %+v", item.Index, item.Document)
+		}
+	}
+
+	if _, err := client.BulkUnpublish(ctx, "post", ids[:2], BulkOptions{}); err != nil {
+		t.Fatalf("BulkUnpublish failed: %v", err)
+	}
+	if count, err := client.Count(ctx, "post", SearchOptions{Status: StatusPublished}); err != nil || count != 3 {
+		t.Errorf("Expected 3 published posts, got %d (%v)", count, err)
+	}
+}
+
+func TestServerExtensionsRequired(t *testing.T) {
+	server := apitotest.NewServer()
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	post := server.Seed("", "post", map[string]interface{}{"title": "Draft"})
+
+	if _, err := client.Publish(ctx, "post", post.ID); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported from Publish, got %v", err)
+	}
+	if _, err := client.ScheduleTransition(ctx, "post", post.ID, StatusPublished, time.Now()); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported from ScheduleTransition, got %v", err)
+	}
+	if result, err := client.BulkPublish(ctx, "post", []string{post.ID}, BulkOptions{}); !errors.Is(err, ErrUnsupported) || result != nil {
+		t.Errorf("Expected ErrUnsupported without a report, got %v", err)
+	}
+	if _, err := client.Drafts(ctx, "post", SearchOptions{}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported from Drafts, got %v", err)
+	}
+	if _, err := client.Count(ctx, "post", SearchOptions{Status: StatusPublished}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported from a status filter, got %v", err)
+	}
+
+	if requests := server.Requests(); len(requests) != 0 {
+		t.Errorf("Expected no request to be sent, got %d", len(requests))
+	}
+}
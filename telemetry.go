@@ -0,0 +1,182 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationName identifies the SDK to OpenTelemetry tracers and meters
+const instrumentationName = "github.com/apito-io/go-internal-sdk"
+
+// Attribute keys recorded on spans and metrics
+const (
+	attrOperationName = attribute.Key("graphql.operation.name")
+	attrOperationType = attribute.Key("graphql.operation.type")
+	attrModel         = attribute.Key("apito.model")
+	attrTenantID      = attribute.Key("apito.tenant_id")
+	attrStatusCode    = attribute.Key("http.response.status_code")
+	attrErrorType     = attribute.Key("error.type")
+)
+
+// telemetry records OpenTelemetry spans and metrics for every operation.
+// Every instrument is a no-op when the corresponding provider is not configured.
+type telemetry struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator // nil when tracing is disabled
+
+	duration     metric.Float64Histogram
+	requestSize  metric.Int64Histogram
+	responseSize metric.Int64Histogram
+	errors       metric.Int64Counter
+}
+
+// newTelemetry creates the tracer and instruments from the configured providers
+func newTelemetry(config Config) *telemetry {
+	t := &telemetry{}
+
+	tracerProvider := config.TracerProvider
+	if tracerProvider == nil {
+		tracerProvider = tracenoop.NewTracerProvider()
+	} else {
+		t.propagator = config.Propagator
+		if t.propagator == nil {
+			t.propagator = propagation.TraceContext{}
+		}
+	}
+	t.tracer = tracerProvider.Tracer(instrumentationName)
+
+	meterProvider := config.MeterProvider
+	if meterProvider == nil {
+		meterProvider = metricnoop.NewMeterProvider()
+	}
+	meter := meterProvider.Meter(instrumentationName)
+
+	// Instrument constructors return usable no-op instruments along with any error
+	var err error
+	t.duration, err = meter.Float64Histogram("apito.client.operation.duration",
+		metric.WithDescription("Duration of Apito operations, including retries"), metric.WithUnit("s"))
+	handleTelemetryError(err)
+	t.requestSize, err = meter.Int64Histogram("apito.client.request.size",
+		metric.WithDescription("Size of encoded GraphQL requests"), metric.WithUnit("By"))
+	handleTelemetryError(err)
+	t.responseSize, err = meter.Int64Histogram("apito.client.response.size",
+		metric.WithDescription("Size of GraphQL response bodies"), metric.WithUnit("By"))
+	handleTelemetryError(err)
+	t.errors, err = meter.Int64Counter("apito.client.errors",
+		metric.WithDescription("Failed Apito operations by error class"), metric.WithUnit("{error}"))
+	handleTelemetryError(err)
+
+	return t
+}
+
+func handleTelemetryError(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// startOperation starts the span of an operation. The returned function ends it
+// and records the operation metrics.
+func (t *telemetry) startOperation(ctx context.Context, op *Operation) (context.Context, func(error)) {
+	name := op.Name
+	if name == "" {
+		name = "apito." + op.Type
+	}
+
+	operationAttrs := []attribute.KeyValue{
+		attrOperationName.String(op.Name),
+		attrOperationType.String(op.Type),
+	}
+	spanAttrs := operationAttrs
+	if model, ok := op.Variables["model"].(string); ok && model != "" {
+		spanAttrs = append(spanAttrs, attrModel.String(model))
+	}
+
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(spanAttrs...))
+	start := time.Now()
+
+	return ctx, func(err error) {
+		// Interceptors may bind the tenant after the span started
+		if op.TenantID != "" {
+			span.SetAttributes(attrTenantID.String(op.TenantID))
+		}
+
+		metricAttrs := operationAttrs
+		if err != nil {
+			class := errorClass(err)
+			metricAttrs = append(metricAttrs, attrErrorType.String(class))
+			span.SetAttributes(attrErrorType.String(class))
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			t.errors.Add(ctx, 1, metric.WithAttributes(metricAttrs...))
+		}
+
+		t.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(metricAttrs...))
+		span.End()
+	}
+}
+
+// inject propagates the trace context of ctx into outgoing request headers
+func (t *telemetry) inject(ctx context.Context, header http.Header) {
+	if t.propagator != nil {
+		t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+	}
+}
+
+// recordRoundTrip records the status code and payload sizes of a single HTTP round-trip
+func (t *telemetry) recordRoundTrip(ctx context.Context, op *Operation, requestSize, responseSize, statusCode int) {
+	trace.SpanFromContext(ctx).SetAttributes(attrStatusCode.Int(statusCode))
+
+	attrs := metric.WithAttributes(attrOperationName.String(op.Name), attrOperationType.String(op.Type))
+	t.requestSize.Record(ctx, int64(requestSize), attrs)
+	t.responseSize.Record(ctx, int64(responseSize), attrs)
+}
+
+// errorClass returns a low-cardinality class for an error, used as the error.type attribute
+func errorClass(err error) string {
+	var (
+		httpErr      *HTTPError
+		gqlErr       *GraphQLError
+		decodeErr    *DecodeError
+		transportErr *transportError
+	)
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, ErrForbidden):
+		return "forbidden"
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, ErrValidation):
+		return "validation"
+	case errors.Is(err, ErrConflict):
+		return "conflict"
+	case errors.As(err, &httpErr):
+		return "http"
+	case errors.As(err, &gqlErr):
+		return "graphql"
+	case errors.As(err, &decodeErr):
+		return "decode"
+	case errors.As(err, &transportErr):
+		return "transport"
+	}
+	return "other"
+}
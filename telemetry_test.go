@@ -0,0 +1,157 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestTelemetrySpansAndPropagation(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		w.Write([]byte(`{"data":{"getModelData":{"results":[],"count":0}}}`))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key", TracerProvider: provider})
+
+	ctx := WithTenant(context.Background(), "tenant-1")
+	if _, err := client.Search(ctx, "product", SearchOptions{}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GetModelData" {
+		t.Errorf("Expected span GetModelData, got %q", span.Name())
+	}
+
+	expected := map[attribute.Key]attribute.Value{
+		attrOperationType: attribute.StringValue("query"),
+		attrModel:         attribute.StringValue("product"),
+		attrTenantID:      attribute.StringValue("tenant-1"),
+		attrStatusCode:    attribute.IntValue(200),
+	}
+	for key, value := range expected {
+		if got, ok := spanAttribute(span, key); !ok || got != value {
+			t.Errorf("Expected %s=%v, got %v", key, value.Emit(), got.Emit())
+		}
+	}
+
+	if traceparent == "" || traceparent[3:35] != span.SpanContext().TraceID().String() {
+		t.Errorf("Expected traceparent for trace %s, got %q", span.SpanContext().TraceID(), traceparent)
+	}
+}
+
+func TestTelemetryErrorsAndMetrics(t *testing.T) {
+	server := newStaticServer(t, http.StatusOK, `{"errors":[{"message":"no such record","extensions":{"code":"NOT_FOUND"}}]}`)
+
+	recorder := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	client := NewClient(Config{
+		BaseURL:        server.URL,
+		APIKey:         "test-key",
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+
+	if _, err := client.GetSingleResource(context.Background(), "product", "1", false); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	span := recorder.Ended()[0]
+	if span.Status().Code != codes.Error {
+		t.Errorf("Expected error status, got %v", span.Status())
+	}
+	if got, _ := spanAttribute(span, attrErrorType); got.AsString() != "not_found" {
+		t.Errorf("Expected error.type not_found, got %q", got.AsString())
+	}
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	found := make(map[string]bool)
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			found[m.Name] = true
+			if m.Name != "apito.client.errors" {
+				continue
+			}
+			sum := m.Data.(metricdata.Sum[int64])
+			if len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1 {
+				t.Errorf("Expected one error recorded, got %+v", sum.DataPoints)
+			}
+			if class, _ := sum.DataPoints[0].Attributes.Value(attrErrorType); class.AsString() != "not_found" {
+				t.Errorf("Expected error class not_found, got %q", class.AsString())
+			}
+		}
+	}
+	for _, name := range []string{"apito.client.operation.duration", "apito.client.request.size", "apito.client.response.size", "apito.client.errors"} {
+		if !found[name] {
+			t.Errorf("Expected metric %s to be recorded", name)
+		}
+	}
+}
+
+func TestTelemetryDisabledByDefault(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Write([]byte(`{"data":{"debug":{"message":"ok"}}}`))
+	}))
+	defer server.Close()
+
+	// A span from the application's own tracer must not be propagated by an uninstrumented client
+	recorder := tracetest.NewSpanRecorder()
+	ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("app").Start(context.Background(), "handler")
+	defer span.End()
+
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	if _, err := client.Debug(ctx, "stage"); err != nil {
+		t.Fatalf("Debug failed: %v", err)
+	}
+	if header.Get("Traceparent") != "" {
+		t.Errorf("Expected no trace propagation, got %q", header.Get("Traceparent"))
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	tests := map[string]error{
+		"canceled":  context.Canceled,
+		"http":      &HTTPError{StatusCode: http.StatusInternalServerError},
+		"forbidden": &HTTPError{StatusCode: http.StatusForbidden},
+		"graphql":   GraphQLErrors{{Message: "boom"}},
+		"decode":    &DecodeError{Err: errUnexpectedFormat},
+		"transport": &RetryError{Attempts: 3, Err: &transportError{err: errors.New("connection refused")}},
+	}
+	for class, err := range tests {
+		if got := errorClass(err); got != class {
+			t.Errorf("Expected %s for %v, got %s", class, err, got)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package goapitosdk
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apito-io/types"
+)
+
+// TenantManagerOptions configures a TenantManager
+type TenantManagerOptions struct {
+	Token         string        // Token passed to GenerateTenantToken when minting tenant tokens
+	RefreshBefore time.Duration // Refresh a cached token this long before it expires (default: 1 minute)
+	DefaultTTL    time.Duration // Lifetime assumed for tokens without an exp claim (default: 10 minutes)
+	// TokenHeader carries the tenant token (default: "X-Apito-Key", in place of the API key,
+	// as the client authenticates every other call). "Authorization" sends "Bearer <token>"
+	// for servers accepting tenant tokens as bearer tokens.
+	TokenHeader string
+}
+
+// TenantManager mints, caches and refreshes tenant tokens and hands out tenant-bound clients.
+// It is safe for concurrent use.
+type TenantManager struct {
+	client *Client
+	opts   TenantManagerOptions
+	now    func() time.Time
+
+	mu       sync.Mutex
+	tokens   map[string]tenantToken
+	inflight map[string]*tokenCall
+}
+
+// tenantToken is a cached tenant token
+type tenantToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// tokenCall is a mint in progress, shared by concurrent callers for the same tenant
+type tokenCall struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+// NewTenantManager creates a tenant manager minting tokens through the client
+func NewTenantManager(client *Client, opts TenantManagerOptions) *TenantManager {
+	if opts.RefreshBefore <= 0 {
+		opts.RefreshBefore = time.Minute
+	}
+	if opts.DefaultTTL <= 0 {
+		opts.DefaultTTL = 10 * time.Minute
+	}
+	if opts.TokenHeader == "" {
+		opts.TokenHeader = "X-Apito-Key"
+	}
+
+	return &TenantManager{
+		client:   client,
+		opts:     opts,
+		now:      time.Now,
+		tokens:   make(map[string]tenantToken),
+		inflight: make(map[string]*tokenCall),
+	}
+}
+
+// Token returns a valid token for the tenant, minting a new one when none is cached
+// or the cached one is about to expire. Concurrent calls for the same tenant share one mint.
+func (m *TenantManager) Token(ctx context.Context, tenantID string) (string, error) {
+	if tenantID == "" {
+		return "", newValidationError("tenant id is required")
+	}
+
+	m.mu.Lock()
+	if cached, ok := m.tokens[tenantID]; ok && m.now().Before(cached.expiresAt.Add(-m.opts.RefreshBefore)) {
+		m.mu.Unlock()
+		return cached.token, nil
+	}
+
+	call, ok := m.inflight[tenantID]
+	if !ok {
+		call = &tokenCall{done: make(chan struct{})}
+		m.inflight[tenantID] = call
+		go m.mint(context.WithoutCancel(ctx), tenantID, call)
+	}
+	m.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// mint generates a token for the tenant and publishes it to the waiting callers.
+// The context is detached from the first caller's cancellation so that one caller
+// giving up does not fail the others.
+func (m *TenantManager) mint(ctx context.Context, tenantID string, call *tokenCall) {
+	call.token, call.err = m.client.GenerateTenantToken(ctx, m.opts.Token, tenantID)
+
+	m.mu.Lock()
+	if call.err == nil {
+		expiresAt, ok := tokenExpiry(call.token)
+		if !ok {
+			expiresAt = m.now().Add(m.opts.DefaultTTL)
+		}
+		m.tokens[tenantID] = tenantToken{token: call.token, expiresAt: expiresAt}
+	}
+	delete(m.inflight, tenantID)
+	m.mu.Unlock()
+
+	close(call.done)
+}
+
+// Invalidate drops the cached token of the tenant so that the next call mints a new one
+func (m *TenantManager) Invalidate(tenantID string) {
+	m.mu.Lock()
+	delete(m.tokens, tenantID)
+	m.mu.Unlock()
+}
+
+// Client returns a client bound to the tenant: every call carries the tenant ID and a
+// valid tenant token. A call rejected as unauthorized is retried once with a fresh token.
+func (m *TenantManager) Client(tenantID string) *Client {
+	return m.client.withOuterInterceptors(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, op *Operation) (*types.GraphQLResponse, error) {
+			attach := func() error {
+				token, err := m.Token(ctx, tenantID)
+				if err != nil {
+					return err
+				}
+				op.TenantID = tenantID
+				if http.CanonicalHeaderKey(m.opts.TokenHeader) == "Authorization" {
+					op.Header.Set("Authorization", "Bearer "+token)
+				} else {
+					op.Header.Set(m.opts.TokenHeader, token)
+				}
+				return nil
+			}
+
+			if err := attach(); err != nil {
+				return nil, err
+			}
+			response, err := next(ctx, op)
+			if !errors.Is(err, ErrUnauthorized) {
+				return response, err
+			}
+
+			m.Invalidate(tenantID)
+			if err := attach(); err != nil {
+				return nil, err
+			}
+			return next(ctx, op)
+		}
+	})
+}
+
+// tokenExpiry returns the expiry of a JWT from its exp claim without verifying the signature
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == "" {
+		return time.Time{}, false
+	}
+
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(exp), 0), true
+}
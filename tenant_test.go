@@ -0,0 +1,191 @@
+package goapitosdk
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testJWT builds an unsigned JWT carrying the given exp claim
+func testJWT(exp time.Time, tenantID string, n int64) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d,"tenant":%q,"n":%d}`, exp.Unix(), tenantID, n)))
+	return header + "." + payload + ".sig"
+}
+
+// newTenantServer mints tokens valid for ttl and answers debug with the key and tenant headers it received
+func newTenantServer(t *testing.T, ttl time.Duration, mints *atomic.Int64) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req capturedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		if strings.Contains(req.Query, "generateTenantToken") {
+			n := mints.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			token := testJWT(time.Now().Add(ttl), req.Variables["tenantId"].(string), n)
+			fmt.Fprintf(w, `{"data":{"generateTenantToken":{"token":%q}}}`, token)
+			return
+		}
+
+		message := r.Header.Get("X-Apito-Key") + "|" + r.Header.Get("Authorization") + "|" + r.Header.Get("X-Apito-Tenant-ID")
+		fmt.Fprintf(w, `{"data":{"debug":{"message":%q}}}`, message)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTenantManagerDeduplicatesMints(t *testing.T) {
+	var mints atomic.Int64
+	server := newTenantServer(t, time.Hour, &mints)
+	manager := NewTenantManager(NewClient(Config{BaseURL: server.URL, APIKey: "test-key"}), TenantManagerOptions{Token: "base"})
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 20)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			token, err := manager.Token(context.Background(), "tenant-1")
+			if err != nil {
+				t.Errorf("Token failed: %v", err)
+			}
+			tokens[i] = token
+		}(i)
+	}
+	wg.Wait()
+
+	if mints.Load() != 1 {
+		t.Errorf("Expected 1 mint, got %d", mints.Load())
+	}
+	for _, token := range tokens {
+		if token != tokens[0] {
+			t.Errorf("Expected every caller to share the token, got %q and %q", token, tokens[0])
+		}
+	}
+
+	if _, err := manager.Token(context.Background(), "tenant-2"); err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if mints.Load() != 2 {
+		t.Errorf("Expected a separate mint per tenant, got %d", mints.Load())
+	}
+}
+
+func TestTenantManagerRefreshesBeforeExpiry(t *testing.T) {
+	var mints atomic.Int64
+	server := newTenantServer(t, 5*time.Minute, &mints)
+	manager := NewTenantManager(NewClient(Config{BaseURL: server.URL, APIKey: "test-key"}), TenantManagerOptions{Token: "base", RefreshBefore: time.Minute})
+
+	now := time.Now()
+	manager.now = func() time.Time { return now }
+
+	first, err := manager.Token(context.Background(), "tenant-1")
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+
+	now = now.Add(3 * time.Minute)
+	if second, _ := manager.Token(context.Background(), "tenant-1"); second != first || mints.Load() != 1 {
+		t.Errorf("Expected the cached token to be reused, got %d mints", mints.Load())
+	}
+
+	// Inside the refresh window
+	now = now.Add(90 * time.Second)
+	third, err := manager.Token(context.Background(), "tenant-1")
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if third == first || mints.Load() != 2 {
+		t.Errorf("Expected the token to be refreshed ahead of expiry, got %d mints", mints.Load())
+	}
+}
+
+func TestTenantManagerClientAttachesToken(t *testing.T) {
+	var mints atomic.Int64
+	server := newTenantServer(t, time.Hour, &mints)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	manager := NewTenantManager(client, TenantManagerOptions{Token: "base"})
+
+	debugMessage := func(result interface{}) string {
+		data, _ := result.(map[string]interface{})
+		message, _ := data["message"].(string)
+		return message
+	}
+
+	result, err := manager.Client("tenant-1").Debug(context.Background(), "stage")
+	if err != nil {
+		t.Fatalf("Debug failed: %v", err)
+	}
+	token, _ := manager.Token(context.Background(), "tenant-1")
+	if expected := token + "||tenant-1"; debugMessage(result) != expected {
+		t.Errorf("Expected %q, got %v", expected, result)
+	}
+
+	bearer := NewTenantManager(client, TenantManagerOptions{Token: "base", TokenHeader: "Authorization"})
+	if result, err = bearer.Client("tenant-1").Debug(context.Background(), "stage"); err != nil {
+		t.Fatalf("Debug failed: %v", err)
+	}
+	token, _ = bearer.Token(context.Background(), "tenant-1")
+	if expected := "test-key|Bearer " + token + "|tenant-1"; debugMessage(result) != expected {
+		t.Errorf("Expected %q, got %v", expected, result)
+	}
+
+	// The base client is left untouched
+	result, err = client.Debug(context.Background(), "stage")
+	if err != nil {
+		t.Fatalf("Debug failed: %v", err)
+	}
+	if debugMessage(result) != "test-key||" {
+		t.Errorf("Expected no tenant headers on the base client, got %v", result)
+	}
+}
+
+func TestTenantManagerRetriesUnauthorizedOnce(t *testing.T) {
+	var mints, calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req capturedRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if strings.Contains(req.Query, "generateTenantToken") {
+			fmt.Fprintf(w, `{"data":{"generateTenantToken":{"token":"token-%d"}}}`, mints.Add(1))
+			return
+		}
+		calls.Add(1)
+		if r.Header.Get("X-Apito-Key") != "token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":{"debug":{"message":"ok"}}}`))
+	}))
+	defer server.Close()
+
+	manager := NewTenantManager(NewClient(Config{BaseURL: server.URL, APIKey: "test-key"}), TenantManagerOptions{Token: "base"})
+	if _, err := manager.Client("tenant-1").Debug(context.Background(), "stage"); err != nil {
+		t.Fatalf("Expected the retry with a fresh token to succeed, got %v", err)
+	}
+	if mints.Load() != 2 || calls.Load() != 2 {
+		t.Errorf("Expected 2 mints and 2 calls, got %d and %d", mints.Load(), calls.Load())
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Unix(1893456000, 0)
+	if got, ok := tokenExpiry(testJWT(exp, "t", 1)); !ok || !got.Equal(exp) {
+		t.Errorf("Expected %v, got %v (%v)", exp, got, ok)
+	}
+	for _, token := range []string{"opaque", "a.!!!.c", "a." + base64.RawURLEncoding.EncodeToString([]byte(`{}`)) + ".c"} {
+		if _, ok := tokenExpiry(token); ok {
+			t.Errorf("Expected no expiry for %q", token)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package goapitosdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apito-io/types"
+)
+
+// epochMillisThreshold tells epoch milliseconds from epoch seconds: as seconds it lies in
+// the year 33658, as milliseconds in 2001
+const epochMillisThreshold = 1e12
+
+// ParseExpireAt parses the expire_at of a document as emitted by the server: an RFC 3339
+// timestamp, or a Unix epoch in seconds or milliseconds. An empty value means the document
+// does not expire and yields the zero time.
+func ParseExpireAt(expireAt string) (time.Time, error) {
+	expireAt = strings.TrimSpace(expireAt)
+	if expireAt == "" {
+		return time.Time{}, nil
+	}
+
+	if epoch, err := strconv.ParseInt(expireAt, 10, 64); err == nil {
+		if epoch == 0 {
+			return time.Time{}, nil
+		}
+		if epoch >= epochMillisThreshold || epoch <= -epochMillisThreshold {
+			return time.UnixMilli(epoch).UTC(), nil
+		}
+		return time.Unix(epoch, 0).UTC(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, expireAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expire_at %q: expected RFC 3339 or a Unix epoch", expireAt)
+	}
+	return t, nil
+}
+
+// DocumentExpiry returns the time a document expires at, or the zero time when it does not
+// expire or its expire_at cannot be parsed
+func DocumentExpiry(doc *types.DefaultDocumentStructure) time.Time {
+	if doc == nil {
+		return time.Time{}
+	}
+	t, _ := ParseExpireAt(doc.ExpireAt)
+	return t
+}
+
+// TypedDocumentExpiry returns the time a typed document expires at, or the zero time when
+// it does not expire. TypedDocumentStructure.ExpireAt holds Unix seconds.
+func TypedDocumentExpiry[T any](doc *types.TypedDocumentStructure[T]) time.Time {
+	if doc == nil || doc.ExpireAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(doc.ExpireAt, 0).UTC()
+}
+
+// expireAtValue is an expire_at as decoded from the wire, where it is either a string or a number
+type expireAtValue string
+
+func (v *expireAtValue) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] != '"' {
+		var number json.Number
+		if err := json.Unmarshal(data, &number); err != nil {
+			return err
+		}
+		*v = expireAtValue(number)
+		return nil
+	}
+	return json.Unmarshal(data, (*string)(v))
+}
+
+// CreateOptions configures a create
+type CreateOptions struct {
+	// TTL makes the document expire this long after it is created, by the client clock (optional)
+	TTL time.Duration
+
+	// ExpireAt makes the document expire at the given time; it takes precedence over TTL (optional)
+	ExpireAt time.Time
+}
+
+// expiry returns the expire_at to send, if any
+func (o CreateOptions) expiry() (string, bool) {
+	return expiryValue(o.ExpireAt, o.TTL)
+}
+
+// expiry returns the expire_at to send, if any. An empty value clears the expiry.
+func (o UpdateOptions) expiry() (string, bool) {
+	if o.ClearExpiry {
+		return "", true
+	}
+	return expiryValue(o.ExpireAt, o.TTL)
+}
+
+func expiryValue(expireAt time.Time, ttl time.Duration) (string, bool) {
+	if expireAt.IsZero() && ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+	if expireAt.IsZero() {
+		return "", false
+	}
+	return expireAt.UTC().Format(time.RFC3339Nano), true
+}
+
+// expiryArgument returns the variable declaration and argument carrying expire_at, and sets
+// the variable, when an expiry is given
+func expiryArgument(variables map[string]interface{}, expireAt string, set bool) (declaration string, argument string) {
+	if !set {
+		return "", ""
+	}
+	variables["expire_at"] = expireAt
+	return ", $expire_at: String", "\n\t\t\t\texpire_at: $expire_at"
+}
+
+// Create creates a new resource like CreateNewResource, with an optional expiry
+func (c *Client) Create(ctx context.Context, request *types.CreateAndUpdateRequest, opts CreateOptions) (*types.DefaultDocumentStructure, error) {
+	var document *types.DefaultDocumentStructure
+	if err := c.createNewResource(ctx, request, opts, &document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// CreateTyped creates a new resource with an optional expiry and returns a typed document
+func CreateTyped[T any](c *Client, ctx context.Context, request *types.CreateAndUpdateRequest, opts CreateOptions) (*types.TypedDocumentStructure[T], error) {
+	var document *typedDocument[T]
+	if err := c.createNewResource(ctx, request, opts, &document); err != nil {
+		return nil, err
+	}
+	return document.typed(), nil
+}
+
+// ExtendTTLOptions configures ExtendTTL
+type ExtendTTLOptions struct {
+	// ServerPrecondition sends the revision the expiry was read at as the expected_revision
+	// argument of the write, see UpdateOptions, failing with a *ConflictError when the document
+	// changed in between. Without it the extension is best-effort: an expiry written between
+	// the read and the write is overwritten.
+	ServerPrecondition bool
+}
+
+// ExtendTTL pushes the expiry of a document back by ttl. A document that does not expire, or
+// whose expiry has passed, gets one ttl from now. Only expire_at is written.
+func (c *Client) ExtendTTL(ctx context.Context, model, _id string, ttl time.Duration, opts ExtendTTLOptions) (*types.DefaultDocumentStructure, error) {
+	if ttl <= 0 {
+		return nil, newValidationError("ttl must be positive, got %s", ttl)
+	}
+
+	current, err := c.GetSingleResource(ctx, model, _id, false)
+	if err != nil {
+		return nil, err
+	}
+	expireAt, err := ParseExpireAt(current.ExpireAt)
+	if err != nil {
+		return nil, err
+	}
+	if now := time.Now(); expireAt.Before(now) {
+		expireAt = now
+	}
+
+	return c.Update(ctx, &types.CreateAndUpdateRequest{
+		ID:      _id,
+		Model:   model,
+		Payload: map[string]interface{}{},
+	}, UpdateOptions{
+		ExpireAt:           expireAt.Add(ttl),
+		ExpectedRevision:   DocumentRevision(current.Meta),
+		ServerPrecondition: opts.ServerPrecondition,
+		revisionRead:       true,
+	})
+}
+
+// ClearTTL removes the expiry of a document so that it is kept until deleted
+func (c *Client) ClearTTL(ctx context.Context, model, _id string) (*types.DefaultDocumentStructure, error) {
+	return c.Update(ctx, &types.CreateAndUpdateRequest{
+		ID:      _id,
+		Model:   model,
+		Payload: map[string]interface{}{},
+	}, UpdateOptions{ClearExpiry: true})
+}
@@ -0,0 +1,208 @@
+package goapitosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apito-io/go-internal-sdk/apitotest"
+	"github.com/apito-io/types"
+)
+
+func TestParseExpireAt(t *testing.T) {
+	want := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"":                          {},
+		"0":                         {},
+		"2024-05-01T12:30:00Z":      want,
+		"2024-05-01T14:30:00+02:00": want,
+		"1714566600":                want,
+		"1714566600000":             want,
+		" 1714566600 ":              want,
+		"2024-05-01T12:30:00.5Z":    want.Add(500 * time.Millisecond),
+	}
+	for input, expected := range cases {
+		got, err := ParseExpireAt(input)
+		if err != nil {
+			t.Errorf("ParseExpireAt(%q) failed: %v", input, err)
+			continue
+		}
+		if !got.Equal(expected) {
+			t.Errorf("ParseExpireAt(%q) = %v, want %v", input, got, expected)
+		}
+	}
+
+	if _, err := ParseExpireAt("next tuesday"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+	if got := parseExpireAt("2024-05-01T12:30:00Z"); got != want.Unix() {
+		t.Errorf("Expected %d Unix seconds, got %d", want.Unix(), got)
+	}
+	if got := DocumentExpiry(&types.DefaultDocumentStructure{ExpireAt: "1714566600000"}); !got.Equal(want) {
+		t.Errorf("Unexpected document expiry %v", got)
+	}
+}
+
+func TestTypedExpireAtFormats(t *testing.T) {
+	want := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	for name, expireAt := range map[string]string{
+		"rfc3339": `"2024-05-01T12:30:00Z"`,
+		"seconds": `1714566600`,
+		"millis":  `1714566600000`,
+		"string":  `"1714566600"`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			document := `{"id":"s1","expire_at":` + expireAt + `,"data":{"title":"Session"}}`
+			server := newStaticServer(t, http.StatusOK, `{"data":{"getSingleData":`+document+`,"getModelData":{"results":[`+document+`],"count":1}}}`)
+			client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+			doc, err := GetSingleResourceTyped[testTask](client, context.Background(), "session", "s1", false)
+			if err != nil {
+				t.Fatalf("GetSingleResourceTyped failed: %v", err)
+			}
+			if doc.ExpireAt != want.Unix() || !TypedDocumentExpiry(doc).Equal(want) {
+				t.Errorf("Expected expiry %v, got %d", want, doc.ExpireAt)
+			}
+
+			// Projected typed reads go through the untyped document
+			projected, err := GetSingleResourceTyped[testTask](client, WithFields(context.Background(), "title"), "session", "s1", false)
+			if err != nil {
+				t.Fatalf("GetSingleResourceTyped with fields failed: %v", err)
+			}
+			if !TypedDocumentExpiry(projected).Equal(want) {
+				t.Errorf("Expected projected expiry %v, got %d", want, projected.ExpireAt)
+			}
+
+			untyped, err := client.GetSingleResource(context.Background(), "session", "s1", false)
+			if err != nil {
+				t.Fatalf("GetSingleResource failed: %v", err)
+			}
+			if !DocumentExpiry(untyped).Equal(want) || untyped.Data["title"] != "Session" {
+				t.Errorf("Expected expiry %v, got %+v", want, untyped)
+			}
+
+			result, err := client.Search(context.Background(), "session", SearchOptions{})
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if len(result.Results) != 1 || !DocumentExpiry(result.Results[0]).Equal(want) {
+				t.Errorf("Expected search results to expire at %v, got %+v", want, result.Results)
+			}
+		})
+	}
+}
+
+func TestDocumentTTL(t *testing.T) {
+	server := apitotest.NewServer()
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var offset time.Duration
+	server.SetClock(func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return time.Now().Add(offset)
+	})
+
+	session, err := CreateTyped[testTask](client, ctx, &types.CreateAndUpdateRequest{Model: "session", Payload: map[string]interface{}{"title": "Login"}}, CreateOptions{TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("CreateTyped failed: %v", err)
+	}
+	expiry := TypedDocumentExpiry(session)
+	if d := time.Until(expiry); d < 59*time.Minute || d > time.Hour {
+		t.Fatalf("Expected the session to expire in an hour, got %v", expiry)
+	}
+
+	extended, err := client.ExtendTTL(ctx, "session", session.ID, 30*time.Minute, ExtendTTLOptions{})
+	if err != nil {
+		t.Fatalf("ExtendTTL failed: %v", err)
+	}
+	if got := DocumentExpiry(extended); !got.Truncate(time.Second).Equal(expiry.Add(30 * time.Minute)) {
+		t.Errorf("Expected the expiry pushed back to %v, got %v", expiry.Add(30*time.Minute), got)
+	}
+	if extended.Data["title"] != "Login" {
+		t.Errorf("Expected the data to be kept, got %v", extended.Data)
+	}
+
+	// Documents are gone once their expiry has passed
+	mu.Lock()
+	offset = 2 * time.Hour
+	mu.Unlock()
+	if _, err := client.GetSingleResource(ctx, "session", session.ID, false); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the session to have expired, got %v", err)
+	}
+	mu.Lock()
+	offset = 0
+	mu.Unlock()
+
+	kept, err := client.Create(ctx, &types.CreateAndUpdateRequest{Model: "session", Payload: map[string]interface{}{"title": "Remember me"}}, CreateOptions{TTL: time.Minute})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	cleared, err := client.ClearTTL(ctx, "session", kept.ID)
+	if err != nil {
+		t.Fatalf("ClearTTL failed: %v", err)
+	}
+	if !DocumentExpiry(cleared).IsZero() || cleared.Data["title"] != "Remember me" {
+		t.Errorf("Expected the expiry to be cleared, got %+v", cleared)
+	}
+
+	// Extending a document without expiry counts from now
+	extended, err = client.ExtendTTL(ctx, "session", kept.ID, time.Minute, ExtendTTLOptions{})
+	if err != nil {
+		t.Fatalf("ExtendTTL failed: %v", err)
+	}
+	if d := time.Until(DocumentExpiry(extended)); d <= 0 || d > time.Minute {
+		t.Errorf("Expected the expiry a minute from now, got %v", DocumentExpiry(extended))
+	}
+
+	if _, err := client.ExtendTTL(ctx, "session", kept.ID, 0, ExtendTTLOptions{}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+
+	// The expiry is only sent when set
+	for _, req := range server.Requests() {
+		_, sent := req.Variables["expire_at"]
+		if (req.OperationName == "CreateNewData" || req.OperationName == "UpdateModelData") && sent != strings.Contains(req.Query, "$expire_at") {
+			t.Errorf("expire_at sent %v but query is: %s", sent, req.Query)
+		}
+		// Changing the expiry leaves the data alone
+		if payload, ok := req.Variables["payload"].(map[string]interface{}); req.OperationName == "UpdateModelData" && (!ok || len(payload) != 0) {
+			t.Errorf("Expected an empty payload, got %v", req.Variables["payload"])
+		}
+	}
+}
+
+func TestExtendTTLNumericExpireAt(t *testing.T) {
+	expireAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	var requests []capturedRequest
+	document := fmt.Sprintf(`{"id":"s1","expire_at":%d,"data":{"title":"Session"},"meta":{"updated_at":"2024-01-01T00:00:00Z"}}`, expireAt.Unix())
+	server := newCapturingServer(t, `{"data":{"getSingleData":`+document+`,"upsertModelData":`+document+`}}`, &requests)
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-key"})
+
+	for _, serverSide := range []bool{false, true} {
+		requests = nil
+		if _, err := client.ExtendTTL(context.Background(), "session", "s1", 30*time.Minute, ExtendTTLOptions{ServerPrecondition: serverSide}); err != nil {
+			t.Fatalf("ExtendTTL failed: %v", err)
+		}
+
+		// One read, then the write, whatever the precondition
+		if len(requests) != 2 {
+			t.Fatalf("server precondition %v: expected 2 requests, got %d", serverSide, len(requests))
+		}
+		sent, _ := requests[1].Variables["expire_at"].(string)
+		if got, err := ParseExpireAt(sent); err != nil || !got.Equal(expireAt.Add(30*time.Minute)) {
+			t.Errorf("server precondition %v: expected the expiry pushed back to %v, got %q", serverSide, expireAt.Add(30*time.Minute), sent)
+		}
+		if _, ok := requests[1].Variables["expected_revision"]; ok != serverSide {
+			t.Errorf("server precondition %v: unexpected variables %v", serverSide, requests[1].Variables)
+		}
+	}
+}
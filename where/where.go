@@ -0,0 +1,321 @@
+// Package where provides a fluent, type-safe builder for the where filters
+// accepted by Apito's getModelData query ($where: JSON).
+//
+//	filter := where.Field("status").Eq("done").
+//		And(where.Field("priority").In("high", "urgent")).
+//		And(where.Field("category.name").Contains("work"))
+//
+//	results, err := client.SearchResources(ctx, "task", map[string]interface{}{"where": filter}, false)
+//
+// A single condition serializes to {"status": {"eq": "done"}}. Conditions joined with And
+// are merged into one object when their fields do not collide and fall back to
+// {"AND": [...]} otherwise; Or always produces {"OR": [...]}. Dotted field names address
+// nested relation fields and serialize as nested objects.
+package where
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Operators understood by the Apito where filter
+const (
+	OpEq          = "eq"
+	OpNe          = "ne"
+	OpGt          = "gt"
+	OpGte         = "gte"
+	OpLt          = "lt"
+	OpLte         = "lte"
+	OpIn          = "in"
+	OpNotIn       = "not_in"
+	OpContains    = "contains"
+	OpNotContains = "not_contains"
+	OpStartsWith  = "starts_with"
+	OpEndsWith    = "ends_with"
+	OpExists      = "exists"
+)
+
+// Logical group keys
+const (
+	KeyAnd = "AND"
+	KeyOr  = "OR"
+)
+
+// ErrInvalid is wrapped by every error returned from Validate
+var ErrInvalid = errors.New("invalid where filter")
+
+type kind int
+
+const (
+	kindEmpty kind = iota
+	kindCondition
+	kindAnd
+	kindOr
+)
+
+// Expr is an immutable where filter expression. The zero value is an empty filter.
+type Expr struct {
+	kind     kind
+	path     []string
+	op       string
+	value    interface{}
+	children []Expr
+}
+
+// FieldRef refers to a document field, optionally nested with dots ("category.name")
+type FieldRef struct {
+	name string
+}
+
+// Field returns a reference to the named field
+func Field(name string) FieldRef {
+	return FieldRef{name: name}
+}
+
+func (f FieldRef) cond(op string, value interface{}) Expr {
+	return Expr{kind: kindCondition, path: strings.Split(f.name, "."), op: op, value: value}
+}
+
+// Eq matches documents where the field equals value
+func (f FieldRef) Eq(value interface{}) Expr { return f.cond(OpEq, value) }
+
+// Ne matches documents where the field does not equal value
+func (f FieldRef) Ne(value interface{}) Expr { return f.cond(OpNe, value) }
+
+// Gt matches documents where the field is greater than value
+func (f FieldRef) Gt(value interface{}) Expr { return f.cond(OpGt, value) }
+
+// Gte matches documents where the field is greater than or equal to value
+func (f FieldRef) Gte(value interface{}) Expr { return f.cond(OpGte, value) }
+
+// Lt matches documents where the field is less than value
+func (f FieldRef) Lt(value interface{}) Expr { return f.cond(OpLt, value) }
+
+// Lte matches documents where the field is less than or equal to value
+func (f FieldRef) Lte(value interface{}) Expr { return f.cond(OpLte, value) }
+
+// Between matches documents where the field lies in the inclusive range [from, to]
+func (f FieldRef) Between(from, to interface{}) Expr {
+	return f.Gte(from).And(f.Lte(to))
+}
+
+// In matches documents where the field equals one of values
+func (f FieldRef) In(values ...interface{}) Expr { return f.cond(OpIn, values) }
+
+// NotIn matches documents where the field equals none of values
+func (f FieldRef) NotIn(values ...interface{}) Expr { return f.cond(OpNotIn, values) }
+
+// Contains matches documents where the field contains the substring or element
+func (f FieldRef) Contains(value interface{}) Expr { return f.cond(OpContains, value) }
+
+// NotContains matches documents where the field does not contain the substring or element
+func (f FieldRef) NotContains(value interface{}) Expr { return f.cond(OpNotContains, value) }
+
+// StartsWith matches documents where the string field starts with prefix
+func (f FieldRef) StartsWith(prefix string) Expr { return f.cond(OpStartsWith, prefix) }
+
+// EndsWith matches documents where the string field ends with suffix
+func (f FieldRef) EndsWith(suffix string) Expr { return f.cond(OpEndsWith, suffix) }
+
+// Exists matches documents where the field is present (or absent when exists is false)
+func (f FieldRef) Exists(exists bool) Expr { return f.cond(OpExists, exists) }
+
+// And combines the expression with others; all of them must match
+func (e Expr) And(others ...Expr) Expr {
+	return And(append([]Expr{e}, others...)...)
+}
+
+// Or combines the expression with others; at least one of them must match
+func (e Expr) Or(others ...Expr) Expr {
+	return Or(append([]Expr{e}, others...)...)
+}
+
+// And returns an expression matching when every expression matches
+func And(exprs ...Expr) Expr {
+	return group(kindAnd, exprs)
+}
+
+// Or returns an expression matching when at least one expression matches
+func Or(exprs ...Expr) Expr {
+	return group(kindOr, exprs)
+}
+
+// group builds a logical group, flattening nested groups of the same kind and dropping empty expressions
+func group(k kind, exprs []Expr) Expr {
+	var children []Expr
+	for _, e := range exprs {
+		switch {
+		case e.kind == kindEmpty:
+			continue
+		case e.kind == k:
+			children = append(children, e.children...)
+		default:
+			children = append(children, e)
+		}
+	}
+
+	switch len(children) {
+	case 0:
+		return Expr{}
+	case 1:
+		return children[0]
+	}
+	return Expr{kind: k, children: children}
+}
+
+// IsEmpty reports whether the expression has no conditions
+func (e Expr) IsEmpty() bool {
+	return e.kind == kindEmpty
+}
+
+// Validate checks the expression for mistakes that the server would otherwise reject
+func (e Expr) Validate() error {
+	var errs []error
+	e.validate(&errs)
+	return errors.Join(errs...)
+}
+
+func (e Expr) validate(errs *[]error) {
+	switch e.kind {
+	case kindAnd, kindOr:
+		for _, child := range e.children {
+			child.validate(errs)
+		}
+		return
+	case kindEmpty:
+		return
+	}
+
+	name := strings.Join(e.path, ".")
+	for _, part := range e.path {
+		if !validIdentifier(part) {
+			*errs = append(*errs, fmt.Errorf("%w: invalid field name %q", ErrInvalid, name))
+			return
+		}
+	}
+
+	switch e.op {
+	case OpIn, OpNotIn:
+		if values, _ := e.value.([]interface{}); len(values) == 0 {
+			*errs = append(*errs, fmt.Errorf("%w: %s on %q requires at least one value", ErrInvalid, e.op, name))
+		}
+	case OpGt, OpGte, OpLt, OpLte:
+		if !orderable(e.value) {
+			*errs = append(*errs, fmt.Errorf("%w: %s on %q requires a number, string or time, got %T", ErrInvalid, e.op, name, e.value))
+		}
+	case OpContains, OpNotContains:
+		if e.value == nil {
+			*errs = append(*errs, fmt.Errorf("%w: %s on %q requires a value", ErrInvalid, e.op, name))
+		}
+	}
+}
+
+// Map returns the JSON-compatible representation sent as the $where variable
+func (e Expr) Map() map[string]interface{} {
+	switch e.kind {
+	case kindCondition:
+		return nest(e.path, map[string]interface{}{e.op: e.value})
+	case kindOr:
+		return map[string]interface{}{KeyOr: childMaps(e.children)}
+	case kindAnd:
+		merged := map[string]interface{}{}
+		for _, child := range e.children {
+			if child.kind == kindOr || !mergeInto(merged, child.Map()) {
+				return map[string]interface{}{KeyAnd: childMaps(e.children)}
+			}
+		}
+		return merged
+	}
+	return map[string]interface{}{}
+}
+
+// MarshalJSON serializes the expression in the shape expected by Apito
+func (e Expr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Map())
+}
+
+// String returns the JSON representation of the expression
+func (e Expr) String() string {
+	b, err := e.MarshalJSON()
+	if err != nil {
+		return fmt.Sprintf("<invalid: %v>", err)
+	}
+	return string(b)
+}
+
+func childMaps(children []Expr) []interface{} {
+	maps := make([]interface{}, len(children))
+	for i, child := range children {
+		maps[i] = child.Map()
+	}
+	return maps
+}
+
+// nest wraps leaf into nested objects following path
+func nest(path []string, leaf map[string]interface{}) map[string]interface{} {
+	result := leaf
+	for i := len(path) - 1; i >= 0; i-- {
+		result = map[string]interface{}{path[i]: result}
+	}
+	return result
+}
+
+// mergeInto deep-merges src into dst and reports false when a key collides
+func mergeInto(dst, src map[string]interface{}) bool {
+	for k, v := range src {
+		existing, ok := dst[k]
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		existingMap, ok1 := existing.(map[string]interface{})
+		srcMap, ok2 := v.(map[string]interface{})
+		if !ok1 || !ok2 || k == KeyAnd || k == KeyOr {
+			return false
+		}
+		// Copy before merging so that a failed merge leaves earlier maps untouched
+		copied := make(map[string]interface{}, len(existingMap))
+		for ck, cv := range existingMap {
+			copied[ck] = cv
+		}
+		if !mergeInto(copied, srcMap) {
+			return false
+		}
+		dst[k] = copied
+	}
+	return true
+}
+
+func validIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func orderable(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	if _, ok := v.(interface{ MarshalText() ([]byte, error) }); ok {
+		return true
+	}
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	}
+	return false
+}
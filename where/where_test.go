@@ -0,0 +1,80 @@
+package where
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSingleCondition(t *testing.T) {
+	got := Field("status").Eq("done").String()
+	want := `{"status":{"eq":"done"}}`
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestAndMergesDistinctFields(t *testing.T) {
+	got := Field("status").Eq("done").And(Field("priority").In("high", "urgent")).String()
+	want := `{"priority":{"in":["high","urgent"]},"status":{"eq":"done"}}`
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestBetweenMergesOperators(t *testing.T) {
+	got := Field("age").Between(18, 65).String()
+	want := `{"age":{"gte":18,"lte":65}}`
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestAndFallsBackOnCollision(t *testing.T) {
+	got := Field("tags").Contains("a").And(Field("tags").Contains("b")).String()
+	want := `{"AND":[{"tags":{"contains":"a"}},{"tags":{"contains":"b"}}]}`
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestOrGroupsAndNestedFields(t *testing.T) {
+	expr := Field("status").Eq("open").And(
+		Or(Field("category.name").Eq("work"), Field("priority").Gte(3)),
+	)
+	got := expr.String()
+	want := `{"AND":[{"status":{"eq":"open"}},{"OR":[{"category":{"name":{"eq":"work"}}},{"priority":{"gte":3}}]}]}`
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestEmptyExpressions(t *testing.T) {
+	if !And().IsEmpty() {
+		t.Error("Expected empty And to be empty")
+	}
+	got := And(Expr{}, Field("a").Exists(true)).String()
+	if got != `{"a":{"exists":true}}` {
+		t.Errorf("Unexpected serialization %s", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Field("created_at").Gt(time.Now()).And(Field("name").StartsWith("x"))
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected valid expression, got %v", err)
+	}
+
+	invalid := []Expr{
+		Field("").Eq(1),
+		Field("bad name").Eq(1),
+		Field("status").In(),
+		Field("price").Gt([]int{1}),
+		Field("tags").Contains(nil),
+	}
+	for _, e := range invalid {
+		if err := e.Validate(); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Expected ErrInvalid for %s, got %v", e, err)
+		}
+	}
+}